@@ -2,6 +2,10 @@ package activities
 
 import (
 	"context"
+
+	"go.temporal.io/sdk/activity"
+
+	"documentation-samples-go/codec/redact"
 )
 
 /*
@@ -10,7 +14,9 @@ Below is an example of an Activity defined as a function.
 */
 
 // SSNTraceActivity is your custom Activity Definition.
-func SSNTraceActivity(ctx context.Context, param string) (*string, error) {
+func SSNTraceActivity(ctx context.Context, param redact.SSN) (*string, error) {
+	// The SSN masks itself, so it is safe to log
+	activity.GetLogger(ctx).Info("Running SSN trace", "SSN", param)
 	// This is where a call to another service is made
 	// Here we are pretending that the service that does SSNTrace returned "pass"
 	result := "pass"
@@ -35,7 +41,7 @@ tags:
 - go sdk
 - code sample
 - activity
-lines: 1-18
+lines: 1-24
 @dacx */
 
 /* @dacx
@@ -46,5 +52,5 @@ description: In the Temporal Go SDK programming model, an Activity Definition is
 tags:
 - go sdk
 - activity
-lines: 20-27
+lines: 26-33
 @dacx */
@@ -8,10 +8,13 @@ import (
 	"github.com/joho/godotenv"
 
 	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/worker"
 
 	"documentation-samples-go/backgroundcheck_boilerplate/activities"
 	"documentation-samples-go/backgroundcheck_boilerplate/workflows"
+	"documentation-samples-go/codec"
+	"documentation-samples-go/codec/redact"
 )
 
 /*
@@ -40,6 +43,11 @@ func main() {
 	namespace := os.Getenv("TEMPORAL_CLOUD_NAMESPACE")
 	port := os.Getenv("TEMPORAL_CLOUD_PORT")
 	hostPort := namespace + ".tmprl.cloud:" + port
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		log.Fatalln("Unable to create Data Converter", err)
+	}
 	// Create a new Temporal Client
 	// Specify Namespace, Hostport and tls certificates in the ConnectionOptions
 	temporalClient, err := client.Dial(client.Options{
@@ -48,6 +56,10 @@ func main() {
 		ConnectionOptions: client.ConnectionOptions{
 			TLS: &tls.Config{Certificates: []tls.Certificate{cert}},
 		},
+		DataConverter: dataConverter,
+		// Scrub SSNs from log lines and from Failures before they are recorded
+		Logger:           redact.NewLogger(nil, redact.SSNPattern),
+		FailureConverter: redact.NewFailureConverter(temporal.GetDefaultFailureConverter(), redact.SSNPattern),
 	})
 	if err != nil {
 		log.Fatalln("Unable to connect to Temporal Cloud.", err)
@@ -82,7 +94,7 @@ id: backgroundcheck-boilerplate-cloud-worker
 title: Run a Temporal Cloud Worker
 description: Provide your Namespace, Address, and certificate key pair to connect to Temporal Cloud.
 label: Cloud Worker
-lines: 1-67
+lines: 1-79
 tags:
 - worker
 - temporal cloud
@@ -95,7 +107,7 @@ id: backgroundcheck-boilerplate-cloud-worker-details
 title: Cloud Worker details
 description: When specifying the Temporal Cloud Namespace, make sure to append the Account Id as it appears in the url of the Cloud UI.
 label: Cloud Worker details
-lines: 69-78
+lines: 81-90
 tags:
 - worker
 - cloud certificate
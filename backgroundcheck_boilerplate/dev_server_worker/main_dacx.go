@@ -4,10 +4,13 @@ import (
 	"log"
 
 	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/worker"
 
 	"documentation-samples-go/backgroundcheck_boilerplate/activities"
 	"documentation-samples-go/backgroundcheck_boilerplate/workflows"
+	"documentation-samples-go/codec"
+	"documentation-samples-go/codec/redact"
 )
 
 /*
@@ -22,10 +25,19 @@ In regards to organization, we recommend keeping Worker code separate from Workf
 */
 
 func main() {
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		log.Fatalln("Unable to create Data Converter", err)
+	}
 	// Initialize a Temporal Client
 	// Specify the Namespace in the Client options
 	clientOptions := client.Options{
-		Namespace: "backgroundcheck_namespace",
+		Namespace:     "backgroundcheck_namespace",
+		DataConverter: dataConverter,
+		// Scrub SSNs from log lines and from Failures before they are recorded
+		Logger:           redact.NewLogger(nil, redact.SSNPattern),
+		FailureConverter: redact.NewFailureConverter(temporal.GetDefaultFailureConverter(), redact.SSNPattern),
 	}
 	temporalClient, err := client.Dial(clientOptions)
 	if err != nil {
@@ -50,7 +62,7 @@ id: backgroundcheck-boilerplate-run-a-dev-server-worker
 title: Run a dev server Worker
 description: Define the code needed to run a Worker Process in Go.
 label: Dev server Worker
-lines: 1-46
+lines: 1-58
 tags:
 - worker
 - developer guide
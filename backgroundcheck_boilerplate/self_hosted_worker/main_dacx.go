@@ -4,10 +4,13 @@ import (
 	"log"
 
 	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/worker"
 
 	"documentation-samples-go/backgroundcheck_boilerplate/activities"
 	"documentation-samples-go/backgroundcheck_boilerplate/workflows"
+	"documentation-samples-go/codec"
+	"documentation-samples-go/codec/redact"
 )
 
 /**
@@ -15,11 +18,20 @@ Set IP address, port, and Namespace in the Temporal Client options.
 **/
 
 func main() {
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		log.Fatalln("Unable to create Data Converter", err)
+	}
 	// Initialize a Temporal Client
 	// Specify the IP, port, and Namespace in the Client options
 	clientOptions := client.Options{
-		HostPort:  "172.18.0.4:7233",
-		Namespace: "backgroundcheck_namespace",
+		HostPort:      "172.18.0.4:7233",
+		Namespace:     "backgroundcheck_namespace",
+		DataConverter: dataConverter,
+		// Scrub SSNs from log lines and from Failures before they are recorded
+		Logger:           redact.NewLogger(nil, redact.SSNPattern),
+		FailureConverter: redact.NewFailureConverter(temporal.GetDefaultFailureConverter(), redact.SSNPattern),
 	}
 	temporalClient, err := client.Dial(clientOptions)
 	if err != nil {
@@ -44,7 +56,7 @@ id: backgroundcheck-boilerplate-self-hosted-worker
 title: Customize Client options
 description: Configure the Temporal Client with the specific IP Address of the Temporal Server on your network.
 label: Self-hosted Client options
-lines: 1-40
+lines: 1-52
 tags:
 - worker
 - self-hosted
@@ -9,6 +9,7 @@ import (
 
 	"documentation-samples-go/backgroundcheck_boilerplate/activities"
 	"documentation-samples-go/backgroundcheck_boilerplate/workflows"
+	"documentation-samples-go/codec/redact"
 )
 
 /*
@@ -48,7 +49,7 @@ We can test Workflow code for the following conditions:
 We can also perform a Workflow Replay test, and we'll provide detailed coverage of this topic in another section.
 */
 
-const ssn string = "555-55-5555"
+const ssn redact.SSN = "555-55-5555"
 
 // Test_BackgroundCheckWorkflow tests the BackgroundCheck Workflow function
 func (s *UnitTestSuite) Test_BackgroundCheckWorkflow() {
@@ -114,7 +115,7 @@ id: backgroundcheck-boilerplate-add-test-framework
 title: Add a testing framework
 description: How to add a testing framework to your Temporal Application.
 label: Test framework
-lines: 1-27, 34-38
+lines: 1-28, 35-39
 tags:
 - testing
 - developer guide
@@ -127,7 +128,7 @@ id: backgroundcheck-boilerplate-add-test-framework-details
 title: Add a testing framework details
 description: How to add a testing framework to your Temporal Application.
 label: Test framework details
-lines: 29-32
+lines: 30-33
 tags:
 - testing
 - developer guide
@@ -144,7 +145,7 @@ tags:
 - testing
 - developer guide
 - go sdk
-lines: 40-73
+lines: 41-74
 @dacx */
 
 /* @dacx
@@ -156,7 +157,7 @@ tags:
 - testing
 - developer guide
 - go sdk
-lines: 75-85
+lines: 76-86
 @dacx */
 
 /* @dacx
@@ -164,7 +165,7 @@ id: backgroundcheck-boilerplate-add-activity-tests
 title: Add Activity function tests
 description: How to test Activity code
 label: Test Activity code
-lines: 87-110
+lines: 88-111
 tags:
 - testing
 - developer guide
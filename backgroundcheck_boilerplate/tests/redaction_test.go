@@ -0,0 +1,78 @@
+package backgroundcheck_boilerplate
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+
+	"documentation-samples-go/backgroundcheck_boilerplate/activities"
+	"documentation-samples-go/backgroundcheck_boilerplate/workflows"
+	"documentation-samples-go/codec/redact"
+)
+
+// captureLogger keeps every log line written in the test environment.
+type captureLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *captureLogger) log(level, msg string, keyvals ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprintln(append([]interface{}{level, msg}, keyvals...)...))
+}
+
+func (l *captureLogger) Debug(msg string, keyvals ...interface{}) { l.log("DEBUG", msg, keyvals...) }
+func (l *captureLogger) Info(msg string, keyvals ...interface{})  { l.log("INFO", msg, keyvals...) }
+func (l *captureLogger) Warn(msg string, keyvals ...interface{})  { l.log("WARN", msg, keyvals...) }
+func (l *captureLogger) Error(msg string, keyvals ...interface{}) { l.log("ERROR", msg, keyvals...) }
+
+func (l *captureLogger) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return strings.Join(l.lines, "")
+}
+
+func Test_BackgroundCheck_MasksSSNInLogs(t *testing.T) {
+	logger := &captureLogger{}
+	testSuite := &testsuite.WorkflowTestSuite{}
+	// Scrub log lines the way the Worker's Client does.
+	testSuite.SetLogger(redact.NewLogger(logger, redact.SSNPattern))
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterActivity(activities.SSNTraceActivity)
+
+	env.ExecuteWorkflow(workflows.BackgroundCheck, ssn)
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Contains(t, logger.String(), "***-**-5555")
+	require.NotContains(t, logger.String(), ssn.Reveal())
+}
+
+func Test_BackgroundCheck_ScrubsSSNFromFailures(t *testing.T) {
+	logger := &captureLogger{}
+	testSuite := &testsuite.WorkflowTestSuite{}
+	// Scrub log lines the way the Worker's Client does.
+	testSuite.SetLogger(redact.NewLogger(logger, redact.SSNPattern))
+	env := testSuite.NewTestWorkflowEnvironment()
+	// The trace service echoes the raw SSN in its error.
+	env.OnActivity(activities.SSNTraceActivity, mock.Anything, ssn).Return(nil, errors.New("trace service rejected "+ssn.Reveal()))
+
+	env.ExecuteWorkflow(workflows.BackgroundCheck, ssn)
+	require.True(t, env.IsWorkflowCompleted())
+	err := env.GetWorkflowError()
+	require.Error(t, err)
+
+	// Convert the error the way the Worker's Client does before recording it.
+	failureConverter := redact.NewFailureConverter(temporal.GetDefaultFailureConverter(), redact.SSNPattern)
+	failure := failureConverter.ErrorToFailure(err)
+	require.Contains(t, failure.String(), "trace service rejected "+redact.Redacted)
+	require.NotContains(t, failure.String(), ssn.Reveal())
+	require.NotContains(t, logger.String(), ssn.Reveal())
+}
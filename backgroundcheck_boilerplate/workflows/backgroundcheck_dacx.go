@@ -6,6 +6,7 @@ import (
 	"go.temporal.io/sdk/workflow"
 
 	"documentation-samples-go/backgroundcheck_boilerplate/activities"
+	"documentation-samples-go/codec/redact"
 )
 
 /*
@@ -14,7 +15,7 @@ The `BackgroundCheck` function below is an example of a basic Workflow Definitio
 */
 
 // BackgroundCheck is your custom Workflow Definition.
-func BackgroundCheck(ctx workflow.Context, param string) (string, error) {
+func BackgroundCheck(ctx workflow.Context, param redact.SSN) (string, error) {
 	// Define the Activity Execution options
 	// StartToCloseTimeout or ScheduleToCloseTimeout must be set
 	activityOptions := workflow.ActivityOptions{
@@ -64,7 +65,7 @@ tags:
 - developer guide
 - workflow
 - code sample
-lines: 1-32
+lines: 1-33
 @dacx */
 
 /* @dacx
@@ -76,5 +77,5 @@ tags:
 - go sdk
 - workflow
 - developer guide
-lines: 34-55
+lines: 35-56
 @dacx */
@@ -2,10 +2,12 @@ package activities
 
 import (
 	"context"
+
+	"documentation-samples-go/codec/redact"
 )
 
 // SSNTraceActivity is your custom Activity Definition.
-func SSNTraceActivity(ctx context.Context, param string) (*string, error) {
+func SSNTraceActivity(ctx context.Context, param redact.SSN) (*string, error) {
 	result := "pass"
 	return &result, nil
 }
@@ -8,10 +8,13 @@ import (
 	"github.com/joho/godotenv"
 
 	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/worker"
 
 	"documentation-samples-go/backgroundcheck_replay/activities"
 	"documentation-samples-go/backgroundcheck_replay/workflows"
+	"documentation-samples-go/codec"
+	"documentation-samples-go/codec/redact"
 )
 
 func main() {
@@ -32,6 +35,11 @@ func main() {
 	namespace := os.Getenv("TEMPORAL_CLOUD_NAMESPACE")
 	port := os.Getenv("TEMPORAL_CLOUD_PORT")
 	hostPort := namespace + ".tmprl.cloud:" + port
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		log.Fatalln("Unable to create Data Converter", err)
+	}
 	// Create a new Temporal Client
 	// Specify Namespace, Hostport and tls certificates in the ConnectionOptions
 	temporalClient, err := client.Dial(client.Options{
@@ -40,6 +48,10 @@ func main() {
 		ConnectionOptions: client.ConnectionOptions{
 			TLS: &tls.Config{Certificates: []tls.Certificate{cert}},
 		},
+		DataConverter: dataConverter,
+		// Scrub SSNs from log lines and from Failures before they are recorded
+		Logger:           redact.NewLogger(nil, redact.SSNPattern),
+		FailureConverter: redact.NewFailureConverter(temporal.GetDefaultFailureConverter(), redact.SSNPattern),
 	})
 	if err != nil {
 		log.Fatalln("Unable to connect to Temporal Cloud.", err)
@@ -36,3 +36,7 @@ require (
 	google.golang.org/protobuf v1.31.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+require documentation-samples-go/codec v0.0.0
+
+replace documentation-samples-go/codec => ../codec
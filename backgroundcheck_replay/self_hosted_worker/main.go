@@ -4,18 +4,30 @@ import (
 	"log"
 
 	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/worker"
 
 	"documentation-samples-go/backgroundcheck_replay/activities"
 	"documentation-samples-go/backgroundcheck_replay/workflows"
+	"documentation-samples-go/codec"
+	"documentation-samples-go/codec/redact"
 )
 
 func main() {
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		log.Fatalln("Unable to create Data Converter", err)
+	}
 	// Initialize a Temporal Client
 	// Specify the IP, port, and Namespace in the Client options
 	clientOptions := client.Options{
-		HostPort:  "172.18.0.4:7233",
-		Namespace: "backgroundcheck_namespace",
+		HostPort:      "172.18.0.4:7233",
+		Namespace:     "backgroundcheck_namespace",
+		DataConverter: dataConverter,
+		// Scrub SSNs from log lines and from Failures before they are recorded
+		Logger:           redact.NewLogger(nil, redact.SSNPattern),
+		FailureConverter: redact.NewFailureConverter(temporal.GetDefaultFailureConverter(), redact.SSNPattern),
 	}
 	temporalClient, err := client.Dial(clientOptions)
 	if err != nil {
@@ -11,6 +11,7 @@ import (
 
 	"documentation-samples-go/backgroundcheck_replay/activities"
 	"documentation-samples-go/backgroundcheck_replay/workflows"
+	"documentation-samples-go/codec/redact"
 )
 
 // UnitTestSuite is a struct that wraps around the testing suites
@@ -27,7 +28,7 @@ func Test_BackgroundCheckApplication(t *testing.T) {
 	suite.Run(t, s)
 }
 
-const ssn string = "555-55-5555"
+const ssn redact.SSN = "555-55-5555"
 
 // Test_BackgroundCheckWorkflow tests the BackgroundCheck Workflow function
 func (s *UnitTestSuite) Test_BackgroundCheckWorkflow() {
@@ -93,7 +94,7 @@ id: add-replay-test-to-background-check-workflow
 title: Add a Replay test
 description: Define the code needed to run a Worker Process in Go.
 label: Add Replay test
-lines: 71-87
+lines: 72-88
 tags:
 - testing
 - replay test
@@ -7,6 +7,7 @@ import (
 	"go.temporal.io/sdk/workflow"
 
 	"documentation-samples-go/backgroundcheck_replay/activities"
+	"documentation-samples-go/codec/redact"
 )
 
 /*
@@ -19,7 +20,7 @@ Use the `workflow.GetLogger` API to log from Workflows to avoid seeing repeated
 */
 
 // BackgroundCheck is your custom Workflow Definition.
-func BackgroundCheck(ctx workflow.Context, param string) (string, error) {
+func BackgroundCheck(ctx workflow.Context, param redact.SSN) (string, error) {
 	// highlight-start
 	// Sleep for 1 minute
 	workflow.GetLogger(ctx).Info("Sleeping for 1 minute...")
@@ -92,7 +93,7 @@ id: add-sleep-for-one-minute
 title: Add a call to sleep
 description: Add a call to sleep for one minute to the beginning of the Workflow.
 label: Add sleep call
-lines: 2-46
+lines: 2-47
 tags:
 - timer
 - sleep
@@ -104,7 +105,7 @@ id: inspect-the-new-event-history
 title: Inspect the new Event History
 description: After making changes to the code, we must update the Event History JSON file to get tests to pass.
 label: Inspect new Event History
-lines: 48-88
+lines: 49-89
 tags:
 - tests
 - replay
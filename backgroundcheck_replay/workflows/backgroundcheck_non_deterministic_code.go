@@ -9,6 +9,7 @@ import (
 	"go.temporal.io/sdk/workflow"
 
 	"documentation-samples-go/backgroundcheck_replay/activities"
+	"documentation-samples-go/codec/redact"
 )
 
 /*
@@ -38,7 +39,7 @@ One way to produce a non-deterministic error is to use a random number to determ
 */
 
 // BackgroundCheckNonDeterministic is an anti-pattern Workflow Definition
-func BackgroundCheckNonDeterministic(ctx workflow.Context, param string) (string, error) {
+func BackgroundCheckNonDeterministic(ctx workflow.Context, param redact.SSN) (string, error) {
 	activityOptions := workflow.ActivityOptions{
 		StartToCloseTimeout: 10 * time.Second,
 	}
@@ -136,7 +137,7 @@ id: backgroundcheck-replay-intrinsic-non-determinism
 title: Intrinsic non-deterministic logic
 description: This kind of logic prevents the Workflow code from executing to completion because the Workflow can take a different code path than the one expected from the Event History.
 label: intrinsic-non-deterministic-logic
-lines: 3-62
+lines: 3-63
 tags:
 - tests
 - replay
@@ -148,7 +149,7 @@ id: backgroundcheck-replay-inspecting-the-non-deterministic-error
 title: Intrinsic non-deterministic logic
 description: This kind of logic prevents the Workflow code from executing to completion because the Workflow can take a different code path than the one expected from the Event History.
 label: intrinsic-non-deterministic-logic
-lines: 64-132
+lines: 65-133
 tags:
 - tests
 - replay
@@ -7,6 +7,7 @@ import (
 	"log"
 
 	"documentation-samples-go/cloud"
+	"documentation-samples-go/codec"
 
 	"go.temporal.io/sdk/client"
 )
@@ -37,6 +38,11 @@ func main() {
 	if err != nil {
 		log.Fatalln("Unable to load cert and key pair.", err)
 	}
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		log.Fatalln("Unable to create Data Converter", err)
+	}
 	// Add the cert to the tls certificates in the ConnectionOptions of the Client
 	temporalClient, err := client.Dial(client.Options{
 		HostPort:  hostPort,
@@ -44,6 +50,7 @@ func main() {
 		ConnectionOptions: client.ConnectionOptions{
 			TLS: &tls.Config{Certificates: []tls.Certificate{cert}},
 		},
+		DataConverter: dataConverter,
 	})
 	if err != nil {
 		log.Fatalln("Unable to connect to Temporal Cloud.", err)
@@ -85,5 +92,5 @@ tags:
 - go sdk
 - code sample
 - temporal cloud
-lines: 1-51, 77
+lines: 1-58, 84
 @dacx */
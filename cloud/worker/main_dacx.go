@@ -8,6 +8,8 @@ import (
 	"go.temporal.io/sdk/worker"
 
 	"documentation-samples-go/cloud"
+	"documentation-samples-go/codec"
+	"documentation-samples-go/metrics"
 )
 
 /*
@@ -36,6 +38,20 @@ func main() {
 	if err != nil {
 		log.Fatalln("Unable to load cert and key pair.", err)
 	}
+	// Report SDK metrics to Prometheus on /metrics.
+	workerMetrics, err := metrics.New()
+	if err != nil {
+		log.Fatalln("Unable to create metrics", err)
+	}
+	defer workerMetrics.Close()
+	go func() {
+		log.Println("Metrics server stopped", workerMetrics.ListenAndServe(":9093"))
+	}()
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		log.Fatalln("Unable to create Data Converter", err)
+	}
 	// Add the cert to the tls certificates in the ConnectionOptions of the Client
 	temporalClient, err := client.Dial(client.Options{
 		HostPort:  hostPort,
@@ -43,6 +59,8 @@ func main() {
 		ConnectionOptions: client.ConnectionOptions{
 			TLS: &tls.Config{Certificates: []tls.Certificate{cert}},
 		},
+		MetricsHandler: workerMetrics.Handler(),
+		DataConverter:  dataConverter,
 	})
 	if err != nil {
 		log.Fatalln("Unable to connect to Temporal Cloud.", err)
@@ -74,5 +92,5 @@ tags:
 - worker
 - how-to
 - temporal cloud
-lines: 1-52, 64
+lines: 1-70, 82
 @dacx */
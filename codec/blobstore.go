@@ -0,0 +1,148 @@
+package codec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+)
+
+// ErrBlobNotFound is returned by BlobStore.Get when there is no blob with the given key.
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore stores the Payloads that the ClaimCheckCodec moves out of the Event History.
+// Implement it to use a shared store, such as an object storage bucket.
+type BlobStore interface {
+	// Put stores data under key.
+	Put(ctx context.Context, key string, data []byte) error
+	// Get returns the data stored under key, or ErrBlobNotFound.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete removes the data stored under key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// validBlobKey matches the keys generated by the ClaimCheckCodec, so that a key read from a Payload can't escape the store.
+var validBlobKey = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+func checkBlobKey(key string) error {
+	if !validBlobKey.MatchString(key) {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	return nil
+}
+
+// FileBlobStore is a BlobStore that keeps each blob in a file in a directory.
+// Every Worker and Client must see the same directory, for example on a shared volume.
+type FileBlobStore struct {
+	dir string
+}
+
+var _ BlobStore = (*FileBlobStore)(nil)
+
+// NewFileBlobStore returns a FileBlobStore in dir, creating dir if needed.
+func NewFileBlobStore(dir string) (*FileBlobStore, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	return &FileBlobStore{dir: dir}, nil
+}
+
+// Put implements BlobStore.
+func (s *FileBlobStore) Put(ctx context.Context, key string, data []byte) error {
+	if err := checkBlobKey(key); err != nil {
+		return err
+	}
+	// Write to a temporary file first, so that readers never see a partial blob.
+	f, err := os.CreateTemp(s.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), filepath.Join(s.dir, key))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+// Get implements BlobStore.
+func (s *FileBlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := checkBlobKey(key); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(s.dir, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrBlobNotFound, key)
+	}
+	return data, err
+}
+
+// Delete implements BlobStore.
+func (s *FileBlobStore) Delete(ctx context.Context, key string) error {
+	if err := checkBlobKey(key); err != nil {
+		return err
+	}
+	err := os.Remove(filepath.Join(s.dir, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// MemoryBlobStore is a BlobStore that keeps blobs in memory.
+// Use it in tests, or when the Client and Workers run in one process.
+type MemoryBlobStore struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+}
+
+var _ BlobStore = (*MemoryBlobStore)(nil)
+
+// NewMemoryBlobStore returns an empty MemoryBlobStore.
+func NewMemoryBlobStore() *MemoryBlobStore {
+	return &MemoryBlobStore{blobs: map[string][]byte{}}
+}
+
+// Put implements BlobStore.
+func (s *MemoryBlobStore) Put(ctx context.Context, key string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blobs[key] = append([]byte(nil), data...)
+	return nil
+}
+
+// Get implements BlobStore.
+func (s *MemoryBlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.blobs[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrBlobNotFound, key)
+	}
+	return data, nil
+}
+
+// Delete implements BlobStore.
+func (s *MemoryBlobStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.blobs, key)
+	return nil
+}
+
+// Len returns the number of blobs in the store.
+func (s *MemoryBlobStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.blobs)
+}
@@ -0,0 +1,270 @@
+package codec
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/proxy"
+	workflowpb "go.temporal.io/api/workflow/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+)
+
+/*
+The Temporal Cluster rejects Payloads that exceed its size limit.
+The Claim-Check Codec moves Payloads at or above a threshold into a BlobStore
+and puts a Payload holding only the blob key into the Event History instead.
+Decoding fetches the blob back, so Workflow and Activity code never sees the reference.
+
+Apply the Claim-Check Codec last on encode, so that the stored blob is already compressed and encrypted:
+with converter.NewCodecDataConverter, put it first in the list of codecs.
+
+Each encoded Payload gets a new random key, but the Temporal Cluster copies Payloads from one Event History to another,
+so a blob can be referenced by several Event Histories and by Schedules.
+Use DeleteClosedWorkflowBlobs to remove the blobs that only Workflow Executions that have closed reference.
+*/
+
+const (
+	// MetadataEncodingClaimCheck is the Metadata encoding of a Payload that holds a blob key.
+	MetadataEncodingClaimCheck = "claim-check/key"
+	// DefaultClaimCheckThreshold is the serialized Payload size, in bytes, from which Payloads are moved to the BlobStore.
+	DefaultClaimCheckThreshold = 256 * 1024
+)
+
+// ClaimCheckCodec is a PayloadCodec that moves large Payloads into a BlobStore.
+type ClaimCheckCodec struct {
+	store     BlobStore
+	threshold int
+}
+
+var _ converter.PayloadCodec = (*ClaimCheckCodec)(nil)
+
+// NewClaimCheckCodec returns a ClaimCheckCodec that moves Payloads of threshold bytes or more into store.
+func NewClaimCheckCodec(store BlobStore, threshold int) *ClaimCheckCodec {
+	return &ClaimCheckCodec{store: store, threshold: threshold}
+}
+
+// Encode implements converter.PayloadCodec.
+func (c *ClaimCheckCodec) Encode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, p := range payloads {
+		if p.Size() < c.threshold {
+			result[i] = p
+			continue
+		}
+		b, err := proto.Marshal(p)
+		if err != nil {
+			return payloads, err
+		}
+		key, err := newBlobKey()
+		if err != nil {
+			return payloads, err
+		}
+		err = c.store.Put(context.Background(), key, b)
+		if err != nil {
+			return payloads, fmt.Errorf("unable to store payload: %w", err)
+		}
+		result[i] = &commonpb.Payload{
+			Metadata: map[string][]byte{converter.MetadataEncoding: []byte(MetadataEncodingClaimCheck)},
+			Data:     []byte(key),
+		}
+	}
+	return result, nil
+}
+
+// Decode implements converter.PayloadCodec.
+func (c *ClaimCheckCodec) Decode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, p := range payloads {
+		// Only if it's our encoding
+		key, ok := blobKey(p)
+		if !ok {
+			result[i] = p
+			continue
+		}
+		b, err := c.store.Get(context.Background(), key)
+		if err != nil {
+			return payloads, fmt.Errorf("unable to fetch payload: %w", err)
+		}
+		result[i] = &commonpb.Payload{}
+		err = proto.Unmarshal(b, result[i])
+		if err != nil {
+			return payloads, err
+		}
+	}
+	return result, nil
+}
+
+func newBlobKey() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// blobKey returns the blob key held by p, if p is a claim-check reference.
+func blobKey(p *commonpb.Payload) (string, bool) {
+	if string(p.GetMetadata()[converter.MetadataEncoding]) != MetadataEncodingClaimCheck {
+		return "", false
+	}
+	return string(p.Data), true
+}
+
+// ClosedWorkflowsQuery is the List Filter that DeleteClosedWorkflowBlobs uses when none is given.
+const ClosedWorkflowsQuery = "ExecutionStatus != 'Running'"
+
+// DeleteClosedWorkflowBlobs deletes from store the blobs referenced by the Event Histories of the
+// closed Workflow Executions that match query, such as "ExecutionStatus != 'Running' AND CloseTime < '2023-01-01T00:00:00Z'",
+// and by nothing else. An empty query matches every closed Workflow Execution.
+// It returns the number of blobs deleted.
+//
+// A blob can be referenced by more than one Event History, and by Schedules: the Temporal Cluster copies Payloads,
+// without encoding them again, into Child Workflows, Signals, retries, Cron and continue-as-new runs, and Schedules keep
+// the arguments of the Workflows they start. So before deleting, it reads the Event History of every other
+// Workflow Execution, open or closed, and every Schedule, and keeps the blobs they reference.
+// Visibility can lag behind the Workflow Executions that just started, so give a query that leaves out the ones that just closed.
+//
+// Run it before the Namespace retention period removes the Event Histories, or the references are lost.
+// After it runs, the Payloads of those Workflow Executions can no longer be decoded.
+func DeleteClosedWorkflowBlobs(ctx context.Context, c client.Client, store BlobStore, query string) (int, error) {
+	if query == "" {
+		query = ClosedWorkflowsQuery
+	}
+	// The blobs of the matching closed Workflow Executions are the candidates for deletion.
+	closed := map[string]bool{}
+	candidates := map[string]bool{}
+	err := forEachWorkflow(ctx, c, query, func(execution *workflowpb.WorkflowExecutionInfo) error {
+		if execution.Status == enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING {
+			return nil
+		}
+		closed[execution.Execution.GetRunId()] = true
+		return historyBlobKeys(ctx, c, execution.Execution, candidates)
+	})
+	if err != nil || len(candidates) == 0 {
+		return 0, err
+	}
+	// Mark the blobs that anything else references.
+	referenced := map[string]bool{}
+	err = forEachWorkflow(ctx, c, "", func(execution *workflowpb.WorkflowExecutionInfo) error {
+		if closed[execution.Execution.GetRunId()] {
+			return nil
+		}
+		return historyBlobKeys(ctx, c, execution.Execution, referenced)
+	})
+	if err != nil {
+		return 0, err
+	}
+	err = scheduleBlobKeys(ctx, c.ScheduleClient(), referenced)
+	if err != nil {
+		return 0, err
+	}
+	// Sweep the others.
+	deleted := 0
+	for key := range candidates {
+		if referenced[key] {
+			continue
+		}
+		err := store.Delete(ctx, key)
+		if err != nil {
+			return deleted, fmt.Errorf("unable to delete blob %s: %w", key, err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// forEachWorkflow calls fn with every Workflow Execution that matches query.
+func forEachWorkflow(ctx context.Context, c client.Client, query string, fn func(*workflowpb.WorkflowExecutionInfo) error) error {
+	var nextPageToken []byte
+	for {
+		response, err := c.ListWorkflow(ctx, &workflowservice.ListWorkflowExecutionsRequest{
+			Query:         query,
+			NextPageToken: nextPageToken,
+		})
+		if err != nil {
+			return err
+		}
+		for _, execution := range response.Executions {
+			err := fn(execution)
+			if err != nil {
+				return err
+			}
+		}
+		nextPageToken = response.NextPageToken
+		if len(nextPageToken) == 0 {
+			return nil
+		}
+	}
+}
+
+// historyBlobKeys adds to keys the blob keys referenced by the Event History of execution.
+func historyBlobKeys(ctx context.Context, c client.Client, execution *commonpb.WorkflowExecution, keys map[string]bool) error {
+	iter := c.GetWorkflowHistory(ctx, execution.GetWorkflowId(), execution.GetRunId(), false, enumspb.HISTORY_EVENT_FILTER_TYPE_ALL_EVENT)
+	for iter.HasNext() {
+		event, err := iter.Next()
+		if err != nil {
+			return fmt.Errorf("unable to read history of workflow %s: %w", execution.GetWorkflowId(), err)
+		}
+		err = proxy.VisitPayloads(ctx, event, proxy.VisitPayloadsOptions{
+			Visitor: func(vpc *proxy.VisitPayloadsContext, payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+				addBlobKeys(keys, payloads...)
+				return payloads, nil
+			},
+			SkipSearchAttributes: true,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scheduleBlobKeys adds to keys the blob keys referenced by the Workflow arguments and memos of every Schedule.
+func scheduleBlobKeys(ctx context.Context, schedules client.ScheduleClient, keys map[string]bool) error {
+	iter, err := schedules.List(ctx, client.ScheduleListOptions{})
+	if err != nil {
+		return err
+	}
+	for iter.HasNext() {
+		entry, err := iter.Next()
+		if err != nil {
+			return err
+		}
+		desc, err := schedules.GetHandle(ctx, entry.ID).Describe(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to describe schedule %s: %w", entry.ID, err)
+		}
+		action, ok := desc.Schedule.Action.(*client.ScheduleWorkflowAction)
+		if !ok {
+			continue
+		}
+		// Described Schedules hold their arguments and memos as Payloads, as stored.
+		for _, arg := range action.Args {
+			if p, ok := arg.(*commonpb.Payload); ok {
+				addBlobKeys(keys, p)
+			}
+		}
+		for _, value := range action.Memo {
+			if p, ok := value.(*commonpb.Payload); ok {
+				addBlobKeys(keys, p)
+			}
+		}
+	}
+	return nil
+}
+
+func addBlobKeys(keys map[string]bool, payloads ...*commonpb.Payload) {
+	for _, p := range payloads {
+		if key, ok := blobKey(p); ok {
+			keys[key] = true
+		}
+	}
+}
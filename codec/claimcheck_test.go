@@ -0,0 +1,212 @@
+package codec
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	historypb "go.temporal.io/api/history/v1"
+	workflowpb "go.temporal.io/api/workflow/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/mocks"
+)
+
+func Test_ClaimCheckCodec_RoundTrip(t *testing.T) {
+	for name, store := range map[string]func(t *testing.T) BlobStore{
+		"Memory": func(t *testing.T) BlobStore { return NewMemoryBlobStore() },
+		"File": func(t *testing.T) BlobStore {
+			store, err := NewFileBlobStore(t.TempDir())
+			require.NoError(t, err)
+			return store
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dataConverter := converter.NewCodecDataConverter(converter.GetDefaultDataConverter(), NewClaimCheckCodec(store(t), 1024))
+			large := strings.Repeat("x", 4096)
+
+			payload, err := dataConverter.ToPayload(large)
+			require.NoError(t, err)
+			require.Equal(t, MetadataEncodingClaimCheck, string(payload.Metadata[converter.MetadataEncoding]))
+			require.Less(t, payload.Size(), 1024)
+
+			var decoded string
+			require.NoError(t, dataConverter.FromPayload(payload, &decoded))
+			require.Equal(t, large, decoded)
+		})
+	}
+}
+
+func Test_ClaimCheckCodec_PassesThroughSmallPayloads(t *testing.T) {
+	store := NewMemoryBlobStore()
+	codec := NewClaimCheckCodec(store, 1024)
+	plain, err := converter.GetDefaultDataConverter().ToPayload("small")
+	require.NoError(t, err)
+
+	encoded, err := codec.Encode([]*commonpb.Payload{plain})
+	require.NoError(t, err)
+	require.Equal(t, plain, encoded[0])
+	require.Equal(t, 0, store.Len())
+}
+
+func Test_ClaimCheckCodec_MissingBlob(t *testing.T) {
+	store := NewMemoryBlobStore()
+	codec := NewClaimCheckCodec(store, 0)
+	plain, err := converter.GetDefaultDataConverter().ToPayload("value")
+	require.NoError(t, err)
+	encoded, err := codec.Encode([]*commonpb.Payload{plain})
+	require.NoError(t, err)
+	key, _ := blobKey(encoded[0])
+	require.NoError(t, store.Delete(context.Background(), key))
+
+	_, err = codec.Decode(encoded)
+	require.ErrorIs(t, err, ErrBlobNotFound)
+}
+
+func Test_FileBlobStore_RejectsInvalidKeys(t *testing.T) {
+	store, err := NewFileBlobStore(t.TempDir())
+	require.NoError(t, err)
+	_, err = store.Get(context.Background(), "../keys.json")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrBlobNotFound)
+}
+
+// historyIterator returns a HistoryEventIterator over events.
+func historyIterator(events ...*historypb.HistoryEvent) *mocks.HistoryEventIterator {
+	iter := &mocks.HistoryEventIterator{}
+	for _, event := range events {
+		iter.On("HasNext").Return(true).Once()
+		iter.On("Next").Return(event, nil).Once()
+	}
+	iter.On("HasNext").Return(false).Once()
+	return iter
+}
+
+func startedEvent(input ...*commonpb.Payload) *historypb.HistoryEvent {
+	return &historypb.HistoryEvent{Attributes: &historypb.HistoryEvent_WorkflowExecutionStartedEventAttributes{
+		WorkflowExecutionStartedEventAttributes: &historypb.WorkflowExecutionStartedEventAttributes{
+			Input: &commonpb.Payloads{Payloads: input},
+		},
+	}}
+}
+
+// fakeSchedules is a client.ScheduleClient with Schedules that start Workflows with the given arguments.
+type fakeSchedules struct {
+	client.ScheduleClient
+	args map[string][]interface{}
+}
+
+func (f *fakeSchedules) List(context.Context, client.ScheduleListOptions) (client.ScheduleListIterator, error) {
+	var entries []*client.ScheduleListEntry
+	for id := range f.args {
+		entries = append(entries, &client.ScheduleListEntry{ID: id})
+	}
+	return &fakeScheduleIterator{entries: entries}, nil
+}
+
+func (f *fakeSchedules) GetHandle(_ context.Context, id string) client.ScheduleHandle {
+	return &fakeScheduleHandle{args: f.args[id]}
+}
+
+type fakeScheduleIterator struct {
+	entries []*client.ScheduleListEntry
+}
+
+func (i *fakeScheduleIterator) HasNext() bool {
+	return len(i.entries) > 0
+}
+
+func (i *fakeScheduleIterator) Next() (*client.ScheduleListEntry, error) {
+	entry := i.entries[0]
+	i.entries = i.entries[1:]
+	return entry, nil
+}
+
+type fakeScheduleHandle struct {
+	client.ScheduleHandle
+	args []interface{}
+}
+
+func (h *fakeScheduleHandle) Describe(context.Context) (*client.ScheduleDescription, error) {
+	return &client.ScheduleDescription{Schedule: client.Schedule{Action: &client.ScheduleWorkflowAction{Args: h.args}}}, nil
+}
+
+func Test_DeleteClosedWorkflowBlobs(t *testing.T) {
+	store := NewMemoryBlobStore()
+	codec := NewClaimCheckCodec(store, 0)
+	plain, err := converter.GetDefaultDataConverter().ToPayload("value")
+	require.NoError(t, err)
+	// Blobs for: the Schedule's argument, an Activity result, a Child Workflow input, a continue-as-new input,
+	// and a Workflow that isn't listed.
+	blobs, err := codec.Encode([]*commonpb.Payload{plain, plain, plain, plain, plain})
+	require.NoError(t, err)
+	scheduleArg, activityResult, childInput, continuedInput, unlisted := blobs[0], blobs[1], blobs[2], blobs[3], blobs[4]
+	require.Equal(t, 5, store.Len())
+
+	// The closed Workflow was started by a Schedule, ran an Activity, started a Child Workflow and continued as new.
+	// The Cluster copied the Payloads it passed on, so only the Activity result is referenced by it alone.
+	closedHistory := historyIterator(
+		startedEvent(scheduleArg),
+		&historypb.HistoryEvent{Attributes: &historypb.HistoryEvent_ActivityTaskCompletedEventAttributes{
+			ActivityTaskCompletedEventAttributes: &historypb.ActivityTaskCompletedEventAttributes{
+				Result: &commonpb.Payloads{Payloads: []*commonpb.Payload{activityResult}},
+			},
+		}},
+		&historypb.HistoryEvent{Attributes: &historypb.HistoryEvent_StartChildWorkflowExecutionInitiatedEventAttributes{
+			StartChildWorkflowExecutionInitiatedEventAttributes: &historypb.StartChildWorkflowExecutionInitiatedEventAttributes{
+				Input: &commonpb.Payloads{Payloads: []*commonpb.Payload{childInput}},
+			},
+		}},
+		&historypb.HistoryEvent{Attributes: &historypb.HistoryEvent_WorkflowExecutionContinuedAsNewEventAttributes{
+			WorkflowExecutionContinuedAsNewEventAttributes: &historypb.WorkflowExecutionContinuedAsNewEventAttributes{
+				Input: &commonpb.Payloads{Payloads: []*commonpb.Payload{continuedInput}},
+			},
+		}},
+	)
+	// The Child Workflow closed later than the query, and the new run is still open.
+	childHistory := historyIterator(startedEvent(childInput))
+	continuedHistory := historyIterator(startedEvent(continuedInput))
+
+	ctx := context.Background()
+	closed := &workflowpb.WorkflowExecutionInfo{
+		Execution: &commonpb.WorkflowExecution{WorkflowId: "closed-workflow-id", RunId: "closed-run-id"},
+		Status:    enumspb.WORKFLOW_EXECUTION_STATUS_CONTINUED_AS_NEW,
+	}
+	child := &workflowpb.WorkflowExecutionInfo{
+		Execution: &commonpb.WorkflowExecution{WorkflowId: "child-workflow-id", RunId: "child-run-id"},
+		Status:    enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED,
+	}
+	continued := &workflowpb.WorkflowExecutionInfo{
+		Execution: &commonpb.WorkflowExecution{WorkflowId: "closed-workflow-id", RunId: "continued-run-id"},
+		Status:    enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING,
+	}
+	c := &mocks.Client{}
+	c.On("ListWorkflow", ctx, &workflowservice.ListWorkflowExecutionsRequest{Query: ClosedWorkflowsQuery}).Return(
+		&workflowservice.ListWorkflowExecutionsResponse{Executions: []*workflowpb.WorkflowExecutionInfo{closed}}, nil)
+	c.On("ListWorkflow", ctx, &workflowservice.ListWorkflowExecutionsRequest{}).Return(
+		&workflowservice.ListWorkflowExecutionsResponse{Executions: []*workflowpb.WorkflowExecutionInfo{closed, child}, NextPageToken: []byte("2")}, nil)
+	c.On("ListWorkflow", ctx, &workflowservice.ListWorkflowExecutionsRequest{NextPageToken: []byte("2")}).Return(
+		&workflowservice.ListWorkflowExecutionsResponse{Executions: []*workflowpb.WorkflowExecutionInfo{continued}}, nil)
+	c.On("GetWorkflowHistory", ctx, "closed-workflow-id", "closed-run-id", false, enumspb.HISTORY_EVENT_FILTER_TYPE_ALL_EVENT).Return(closedHistory).Once()
+	c.On("GetWorkflowHistory", ctx, "child-workflow-id", "child-run-id", false, enumspb.HISTORY_EVENT_FILTER_TYPE_ALL_EVENT).Return(childHistory).Once()
+	c.On("GetWorkflowHistory", ctx, "closed-workflow-id", "continued-run-id", false, enumspb.HISTORY_EVENT_FILTER_TYPE_ALL_EVENT).Return(continuedHistory).Once()
+	c.On("ScheduleClient").Return(&fakeSchedules{args: map[string][]interface{}{"schedule-id": {scheduleArg}}})
+
+	deleted, err := DeleteClosedWorkflowBlobs(ctx, c, store, "")
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+	require.Equal(t, 4, store.Len())
+	_, err = codec.Decode([]*commonpb.Payload{activityResult})
+	require.ErrorIs(t, err, ErrBlobNotFound)
+	decoded, err := codec.Decode([]*commonpb.Payload{scheduleArg, childInput, continuedInput, unlisted})
+	require.NoError(t, err)
+	require.Equal(t, []*commonpb.Payload{plain, plain, plain, plain}, decoded)
+	c.AssertExpectations(t)
+	for _, iter := range []*mocks.HistoryEventIterator{closedHistory, childHistory, continuedHistory} {
+		iter.AssertExpectations(t)
+	}
+}
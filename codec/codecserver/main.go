@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"documentation-samples-go/codec"
+)
+
+// codecserver serves the repo's Payload Codecs to the Temporal Web UI and CLI.
+//
+// Set the Codec Server endpoint in the Web UI, or pass it to the CLI:
+//
+//	temporal workflow show -w your-workflow-id --codec-endpoint http://localhost:8081
+//
+// Set CODEC_SERVER_TOKEN to require a bearer token.
+func main() {
+	addr := flag.String("addr", ":8081", "address to listen on")
+	origins := flag.String("origins", "http://localhost:8233", "comma-separated origins allowed by CORS, such as the Web UI; * allows any origin, without credentials")
+	flag.Parse()
+
+	codecs, err := codec.NewCodecs()
+	if err != nil {
+		log.Fatalln("Unable to create codecs", err)
+	}
+	handler := codec.NewServer(codecs, codec.ServerOptions{
+		AllowedOrigins: strings.Split(*origins, ","),
+		BearerToken:    os.Getenv("CODEC_SERVER_TOKEN"),
+	})
+	log.Println("Codec server listening on", *addr)
+	err = http.ListenAndServe(*addr, handler)
+	if err != nil {
+		log.Fatalln("Unable to run codec server", err)
+	}
+}
@@ -0,0 +1,129 @@
+package codec
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gogo/protobuf/proto"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+)
+
+/*
+Large Activity results, such as lists of file metadata, make the Event History bigger than it needs to be.
+The Compression Codec gzips Payloads whose serialized size is at least a threshold.
+Smaller Payloads, and Payloads that don't get smaller when compressed, pass through unchanged.
+
+Compress before encrypting: encrypted data doesn't compress.
+With converter.NewCodecDataConverter, codecs are applied last to first on encode, so put the Compression Codec after the Encryption Codec.
+*/
+
+const (
+	// MetadataEncodingGzip is the Metadata encoding of a Payload compressed by the CompressionCodec.
+	MetadataEncodingGzip = "binary/gzip"
+	// DefaultCompressionThreshold is the serialized Payload size, in bytes, from which Payloads are compressed.
+	DefaultCompressionThreshold = 1024
+	// DefaultMaxDecompressedSize is the largest serialized Payload, in bytes, that Decode inflates.
+	DefaultMaxDecompressedSize = 64 * 1024 * 1024
+)
+
+// ErrDecompressedSizeExceeded is returned by CompressionCodec.Decode when a Payload inflates to more than the maximum decompressed size.
+var ErrDecompressedSizeExceeded = errors.New("decompressed payload exceeds the maximum size")
+
+// CompressionCodec is a PayloadCodec that gzips Payloads at or above a size threshold.
+type CompressionCodec struct {
+	threshold       int
+	level           int
+	maxDecompressed int
+}
+
+var _ converter.PayloadCodec = (*CompressionCodec)(nil)
+
+// NewCompressionCodec returns a CompressionCodec that compresses Payloads of threshold bytes or more
+// with the given gzip level, such as gzip.DefaultCompression.
+// Decode fails on Payloads that inflate to more than maxDecompressed bytes, such as DefaultMaxDecompressedSize,
+// so that a small crafted Payload can't exhaust the memory of a Worker or the Codec Server.
+func NewCompressionCodec(threshold, level, maxDecompressed int) (*CompressionCodec, error) {
+	if threshold < 0 {
+		return nil, fmt.Errorf("compression threshold must not be negative, got %d", threshold)
+	}
+	if maxDecompressed <= 0 {
+		return nil, fmt.Errorf("maximum decompressed size must be positive, got %d", maxDecompressed)
+	}
+	// Check the level once here rather than on every Encode.
+	_, err := gzip.NewWriterLevel(io.Discard, level)
+	if err != nil {
+		return nil, err
+	}
+	return &CompressionCodec{threshold: threshold, level: level, maxDecompressed: maxDecompressed}, nil
+}
+
+// Encode implements converter.PayloadCodec.
+func (c *CompressionCodec) Encode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, p := range payloads {
+		if p.Size() < c.threshold {
+			result[i] = p
+			continue
+		}
+		b, err := proto.Marshal(p)
+		if err != nil {
+			return payloads, err
+		}
+		var buf bytes.Buffer
+		w, err := gzip.NewWriterLevel(&buf, c.level)
+		if err != nil {
+			return payloads, err
+		}
+		_, err = w.Write(b)
+		if err == nil {
+			err = w.Close()
+		}
+		if err != nil {
+			return payloads, err
+		}
+		// Keep the original if compression doesn't save space.
+		if buf.Len() >= len(b) {
+			result[i] = p
+			continue
+		}
+		result[i] = &commonpb.Payload{
+			Metadata: map[string][]byte{converter.MetadataEncoding: []byte(MetadataEncodingGzip)},
+			Data:     buf.Bytes(),
+		}
+	}
+	return result, nil
+}
+
+// Decode implements converter.PayloadCodec.
+func (c *CompressionCodec) Decode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, p := range payloads {
+		// Only if it's our encoding
+		if string(p.Metadata[converter.MetadataEncoding]) != MetadataEncodingGzip {
+			result[i] = p
+			continue
+		}
+		r, err := gzip.NewReader(bytes.NewReader(p.Data))
+		if err != nil {
+			return payloads, fmt.Errorf("unable to decompress payload: %w", err)
+		}
+		// Read one byte past the limit to tell a Payload of exactly maxDecompressed bytes from a larger one.
+		b, err := io.ReadAll(io.LimitReader(r, int64(c.maxDecompressed)+1))
+		if err != nil {
+			return payloads, fmt.Errorf("unable to decompress payload: %w", err)
+		}
+		if len(b) > c.maxDecompressed {
+			return payloads, fmt.Errorf("unable to decompress payload: %w", ErrDecompressedSizeExceeded)
+		}
+		result[i] = &commonpb.Payload{}
+		err = proto.Unmarshal(b, result[i])
+		if err != nil {
+			return payloads, err
+		}
+	}
+	return result, nil
+}
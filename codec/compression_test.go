@@ -0,0 +1,198 @@
+package codec
+
+import (
+	"compress/gzip"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+)
+
+// fileMetadata is representative of the file metadata lists returned by Activities.
+type fileMetadata struct {
+	Name     string
+	Path     string
+	Size     int64
+	Modified time.Time
+	Checksum string
+}
+
+// activityResult is representative of YourActivityResultObject.
+type activityResult struct {
+	ResultFieldX string
+	ResultFieldY int
+}
+
+func newFileMetadataList(n int) []fileMetadata {
+	files := make([]fileMetadata, n)
+	for i := range files {
+		files[i] = fileMetadata{
+			Name:     fmt.Sprintf("file-%04d.csv", i),
+			Path:     fmt.Sprintf("/data/exports/2023/%02d/file-%04d.csv", i%12+1, i),
+			Size:     int64(1024 * (i + 1)),
+			Modified: time.Date(2023, 1, 1, 0, 0, i, 0, time.UTC),
+			Checksum: fmt.Sprintf("%064x", i),
+		}
+	}
+	return files
+}
+
+func Test_CompressionCodec_RoundTrip(t *testing.T) {
+	codec, err := NewCompressionCodec(DefaultCompressionThreshold, gzip.DefaultCompression, DefaultMaxDecompressedSize)
+	require.NoError(t, err)
+	dataConverter := converter.NewCodecDataConverter(converter.GetDefaultDataConverter(), codec)
+	files := newFileMetadataList(100)
+	plain, err := converter.GetDefaultDataConverter().ToPayload(files)
+	require.NoError(t, err)
+
+	payload, err := dataConverter.ToPayload(files)
+	require.NoError(t, err)
+	require.Equal(t, MetadataEncodingGzip, string(payload.Metadata[converter.MetadataEncoding]))
+	require.Less(t, payload.Size(), plain.Size())
+
+	var decoded []fileMetadata
+	require.NoError(t, dataConverter.FromPayload(payload, &decoded))
+	require.Equal(t, files, decoded)
+}
+
+func Test_CompressionCodec_PassesThroughSmallPayloads(t *testing.T) {
+	codec, err := NewCompressionCodec(DefaultCompressionThreshold, gzip.DefaultCompression, DefaultMaxDecompressedSize)
+	require.NoError(t, err)
+	plain, err := converter.GetDefaultDataConverter().ToPayload(activityResult{ResultFieldX: "Success", ResultFieldY: 1})
+	require.NoError(t, err)
+
+	encoded, err := codec.Encode([]*commonpb.Payload{plain})
+	require.NoError(t, err)
+	require.Equal(t, plain, encoded[0])
+	decoded, err := codec.Decode(encoded)
+	require.NoError(t, err)
+	require.Equal(t, plain, decoded[0])
+}
+
+func Test_CompressionCodec_KeepsIncompressiblePayloads(t *testing.T) {
+	codec, err := NewCompressionCodec(0, gzip.DefaultCompression, DefaultMaxDecompressedSize)
+	require.NoError(t, err)
+	plain, err := converter.GetDefaultDataConverter().ToPayload("x")
+	require.NoError(t, err)
+
+	encoded, err := codec.Encode([]*commonpb.Payload{plain})
+	require.NoError(t, err)
+	require.Equal(t, plain, encoded[0])
+}
+
+func Test_CompressionCodec_InvalidOptions(t *testing.T) {
+	_, err := NewCompressionCodec(-1, gzip.DefaultCompression, DefaultMaxDecompressedSize)
+	require.Error(t, err)
+	_, err = NewCompressionCodec(DefaultCompressionThreshold, 42, DefaultMaxDecompressedSize)
+	require.Error(t, err)
+	_, err = NewCompressionCodec(DefaultCompressionThreshold, gzip.DefaultCompression, 0)
+	require.Error(t, err)
+}
+
+func Test_CompressionCodec_RejectsPayloadsOverMaxDecompressedSize(t *testing.T) {
+	codec, err := NewCompressionCodec(0, gzip.DefaultCompression, DefaultMaxDecompressedSize)
+	require.NoError(t, err)
+	// 1 MiB of zeros gzips to about 1 KiB.
+	plain, err := converter.GetDefaultDataConverter().ToPayload(make([]byte, 1024*1024))
+	require.NoError(t, err)
+	encoded, err := codec.Encode([]*commonpb.Payload{plain})
+	require.NoError(t, err)
+	require.Equal(t, MetadataEncodingGzip, string(encoded[0].Metadata[converter.MetadataEncoding]))
+
+	limited, err := NewCompressionCodec(0, gzip.DefaultCompression, plain.Size()-1)
+	require.NoError(t, err)
+	_, err = limited.Decode(encoded)
+	require.ErrorIs(t, err, ErrDecompressedSizeExceeded)
+
+	exact, err := NewCompressionCodec(0, gzip.DefaultCompression, plain.Size())
+	require.NoError(t, err)
+	decoded, err := exact.Decode(encoded)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(plain, decoded[0]))
+}
+
+func Test_CompressionCodec_ChainedWithEncryption(t *testing.T) {
+	encryption, err := NewEncryptionCodec(newTestKeyring(t, "key-1"))
+	require.NoError(t, err)
+	compression, err := NewCompressionCodec(DefaultCompressionThreshold, gzip.DefaultCompression, DefaultMaxDecompressedSize)
+	require.NoError(t, err)
+	dataConverter := converter.NewCodecDataConverter(converter.GetDefaultDataConverter(), encryption, compression)
+	files := newFileMetadataList(100)
+	plain, err := converter.GetDefaultDataConverter().ToPayload(files)
+	require.NoError(t, err)
+
+	payload, err := dataConverter.ToPayload(files)
+	require.NoError(t, err)
+	require.Equal(t, MetadataEncodingEncrypted, string(payload.Metadata[converter.MetadataEncoding]))
+	// Compressed before it was encrypted.
+	require.Less(t, payload.Size(), plain.Size())
+
+	var decoded []fileMetadata
+	require.NoError(t, dataConverter.FromPayload(payload, &decoded))
+	require.Equal(t, files, decoded)
+}
+
+type benchmarkPayload struct {
+	name    string
+	payload *commonpb.Payload
+}
+
+func benchmarkPayloads(b *testing.B) []benchmarkPayload {
+	values := []struct {
+		name  string
+		value interface{}
+	}{
+		{"ActivityResult", activityResult{ResultFieldX: "Success", ResultFieldY: 1}},
+		{"FileMetadata10", newFileMetadataList(10)},
+		{"FileMetadata1000", newFileMetadataList(1000)},
+	}
+	payloads := make([]benchmarkPayload, len(values))
+	for i, v := range values {
+		payload, err := converter.GetDefaultDataConverter().ToPayload(v.value)
+		require.NoError(b, err)
+		payloads[i] = benchmarkPayload{name: v.name, payload: payload}
+	}
+	return payloads
+}
+
+func BenchmarkCompressionCodec_Encode(b *testing.B) {
+	codec, err := NewCompressionCodec(DefaultCompressionThreshold, gzip.DefaultCompression, DefaultMaxDecompressedSize)
+	require.NoError(b, err)
+	for _, bp := range benchmarkPayloads(b) {
+		payload := bp.payload
+		b.Run(bp.name, func(b *testing.B) {
+			b.SetBytes(int64(payload.Size()))
+			var encoded []*commonpb.Payload
+			for i := 0; i < b.N; i++ {
+				encoded, err = codec.Encode([]*commonpb.Payload{payload})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(encoded[0].Size())/float64(payload.Size()), "ratio")
+		})
+	}
+}
+
+func BenchmarkCompressionCodec_Decode(b *testing.B) {
+	codec, err := NewCompressionCodec(DefaultCompressionThreshold, gzip.DefaultCompression, DefaultMaxDecompressedSize)
+	require.NoError(b, err)
+	for _, bp := range benchmarkPayloads(b) {
+		encoded, err := codec.Encode([]*commonpb.Payload{bp.payload})
+		require.NoError(b, err)
+		size := bp.payload.Size()
+		b.Run(bp.name, func(b *testing.B) {
+			b.SetBytes(int64(size))
+			for i := 0; i < b.N; i++ {
+				_, err := codec.Decode(encoded)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
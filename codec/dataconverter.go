@@ -0,0 +1,75 @@
+package codec
+
+import (
+	"compress/gzip"
+	"fmt"
+	"os"
+
+	"go.temporal.io/sdk/converter"
+)
+
+/*
+Every Client in this repo sets the same Data Converter, so that Workers, starters and the codec server agree on how Payloads are encoded:
+
+	dataConverter, err := codec.NewDataConverter()
+	...
+	temporalClient, err := client.Dial(client.Options{DataConverter: dataConverter})
+
+The keyfile is read from the path in the TEMPORAL_CODEC_KEYFILE environment variable, or from keys.json in the working directory.
+Create it with `go run documentation-samples-go/codec/keygen`.
+
+Set TEMPORAL_CLAIM_CHECK_DIR to a directory that every Worker and Client shares to move large Payloads out of the Event History.
+*/
+
+const (
+	// KeyfileEnvVar is the environment variable that holds the path of the keyfile.
+	KeyfileEnvVar = "TEMPORAL_CODEC_KEYFILE"
+	// DefaultKeyfile is the keyfile path used when KeyfileEnvVar is not set.
+	DefaultKeyfile = "keys.json"
+	// ClaimCheckDirEnvVar is the environment variable that holds the directory of the claim-check FileBlobStore.
+	// When it is not set, Payloads are not moved out of the Event History.
+	ClaimCheckDirEnvVar = "TEMPORAL_CLAIM_CHECK_DIR"
+)
+
+// KeyfilePath returns the path of the keyfile.
+func KeyfilePath() string {
+	if path := os.Getenv(KeyfileEnvVar); path != "" {
+		return path
+	}
+	return DefaultKeyfile
+}
+
+// NewCodecs returns the Payload Codecs used by the samples, outermost first.
+// Payloads are compressed, then encrypted, then moved to the claim-check store if ClaimCheckDirEnvVar is set.
+func NewCodecs() ([]converter.PayloadCodec, error) {
+	keyring, err := LoadKeyring(KeyfilePath())
+	if err != nil {
+		return nil, fmt.Errorf("unable to load keyfile (set %s or run keygen): %w", KeyfileEnvVar, err)
+	}
+	encryption, err := NewEncryptionCodec(keyring)
+	if err != nil {
+		return nil, err
+	}
+	compression, err := NewCompressionCodec(DefaultCompressionThreshold, gzip.DefaultCompression, DefaultMaxDecompressedSize)
+	if err != nil {
+		return nil, err
+	}
+	codecs := []converter.PayloadCodec{encryption, compression}
+	if dir := os.Getenv(ClaimCheckDirEnvVar); dir != "" {
+		store, err := NewFileBlobStore(dir)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open claim-check store: %w", err)
+		}
+		codecs = append([]converter.PayloadCodec{NewClaimCheckCodec(store, DefaultClaimCheckThreshold)}, codecs...)
+	}
+	return codecs, nil
+}
+
+// NewDataConverter returns the default Data Converter wrapped with the codecs from NewCodecs.
+func NewDataConverter() (converter.DataConverter, error) {
+	codecs, err := NewCodecs()
+	if err != nil {
+		return nil, err
+	}
+	return converter.NewCodecDataConverter(converter.GetDefaultDataConverter(), codecs...), nil
+}
@@ -0,0 +1,175 @@
+// Package codec provides the Payload Codecs used by the samples in this repo.
+package codec
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gogo/protobuf/proto"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+)
+
+/*
+A Payload Codec transforms Payloads after the Data Converter serializes them and before they are sent to the Temporal Cluster.
+The Encryption Codec below encrypts every Payload with AES-256-GCM, so Workflow inputs such as an SSN are not stored in plaintext in the Event History.
+
+Each encrypted Payload records the Id of the key that encrypted it in its Metadata.
+To rotate keys, add a new key to the keyfile and make it active:
+
+- New Payloads are encrypted with the active key.
+- Existing Payloads are still decrypted with the key they name, as long as that key stays in the keyfile.
+*/
+
+const (
+	// MetadataEncodingEncrypted is the Metadata encoding of a Payload encrypted by the EncryptionCodec.
+	MetadataEncodingEncrypted = "binary/encrypted"
+	// MetadataEncryptionKeyID is the Metadata key that holds the Id of the encryption key.
+	MetadataEncryptionKeyID = "encryption-key-id"
+)
+
+// Keyring holds the active encryption key and every key that can still be used to decrypt.
+type Keyring struct {
+	// ActiveKeyID is the Id of the key used to encrypt new Payloads.
+	ActiveKeyID string `json:"activeKeyId"`
+	// Keys maps key Ids to 32 byte AES-256 keys.
+	Keys map[string][]byte `json:"keys"`
+}
+
+// LoadKeyring reads a Keyring from a JSON keyfile.
+// Keys are base64 encoded in the file.
+func LoadKeyring(path string) (*Keyring, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var keyring Keyring
+	err = json.Unmarshal(b, &keyring)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse keyfile %s: %w", path, err)
+	}
+	return &keyring, nil
+}
+
+// Save writes the Keyring to a JSON keyfile that only the owner can read.
+func (k *Keyring) Save(path string) error {
+	b, err := json.MarshalIndent(k, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0600)
+}
+
+// Rotate adds a new random key with the given Id and makes it the active key.
+// The previous keys are kept so that existing Payloads can still be decrypted.
+func (k *Keyring) Rotate(keyID string) error {
+	if _, ok := k.Keys[keyID]; ok {
+		return fmt.Errorf("key %q already exists", keyID)
+	}
+	key := make([]byte, 32)
+	_, err := io.ReadFull(rand.Reader, key)
+	if err != nil {
+		return err
+	}
+	if k.Keys == nil {
+		k.Keys = map[string][]byte{}
+	}
+	k.Keys[keyID] = key
+	k.ActiveKeyID = keyID
+	return nil
+}
+
+// EncryptionCodec is a PayloadCodec that encrypts Payloads with AES-256-GCM.
+type EncryptionCodec struct {
+	activeKeyID string
+	aeads       map[string]cipher.AEAD
+}
+
+var _ converter.PayloadCodec = (*EncryptionCodec)(nil)
+
+// NewEncryptionCodec returns an EncryptionCodec that encrypts with the active key of keyring
+// and decrypts with any of its keys.
+func NewEncryptionCodec(keyring *Keyring) (*EncryptionCodec, error) {
+	if _, ok := keyring.Keys[keyring.ActiveKeyID]; !ok {
+		return nil, fmt.Errorf("active key %q is not in the keyring", keyring.ActiveKeyID)
+	}
+	aeads := make(map[string]cipher.AEAD, len(keyring.Keys))
+	for keyID, key := range keyring.Keys {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("key %q: must be 32 bytes for AES-256, got %d", keyID, len(key))
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", keyID, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", keyID, err)
+		}
+		aeads[keyID] = aead
+	}
+	return &EncryptionCodec{activeKeyID: keyring.ActiveKeyID, aeads: aeads}, nil
+}
+
+// Encode implements converter.PayloadCodec.
+func (e *EncryptionCodec) Encode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	aead := e.aeads[e.activeKeyID]
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, p := range payloads {
+		b, err := proto.Marshal(p)
+		if err != nil {
+			return payloads, err
+		}
+		nonce := make([]byte, aead.NonceSize())
+		_, err = io.ReadFull(rand.Reader, nonce)
+		if err != nil {
+			return payloads, err
+		}
+		// The nonce is stored in front of the ciphertext.
+		result[i] = &commonpb.Payload{
+			Metadata: map[string][]byte{
+				converter.MetadataEncoding: []byte(MetadataEncodingEncrypted),
+				MetadataEncryptionKeyID:    []byte(e.activeKeyID),
+			},
+			Data: aead.Seal(nonce, nonce, b, nil),
+		}
+	}
+	return result, nil
+}
+
+// Decode implements converter.PayloadCodec.
+func (e *EncryptionCodec) Decode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, p := range payloads {
+		// Only if it's our encoding
+		if string(p.Metadata[converter.MetadataEncoding]) != MetadataEncodingEncrypted {
+			result[i] = p
+			continue
+		}
+		keyID := string(p.Metadata[MetadataEncryptionKeyID])
+		aead, ok := e.aeads[keyID]
+		if !ok {
+			return payloads, fmt.Errorf("no decryption key %q in the keyring", keyID)
+		}
+		if len(p.Data) < aead.NonceSize() {
+			return payloads, errors.New("encrypted payload is too short")
+		}
+		nonce, ciphertext := p.Data[:aead.NonceSize()], p.Data[aead.NonceSize():]
+		b, err := aead.Open(nil, nonce, ciphertext, nil)
+		if err != nil {
+			return payloads, fmt.Errorf("unable to decrypt payload with key %q: %w", keyID, err)
+		}
+		result[i] = &commonpb.Payload{}
+		err = proto.Unmarshal(b, result[i])
+		if err != nil {
+			return payloads, err
+		}
+	}
+	return result, nil
+}
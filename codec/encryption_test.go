@@ -0,0 +1,108 @@
+package codec
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+)
+
+func newTestKeyring(t *testing.T, keyIDs ...string) *Keyring {
+	keyring := &Keyring{}
+	for _, keyID := range keyIDs {
+		require.NoError(t, keyring.Rotate(keyID))
+	}
+	return keyring
+}
+
+func Test_EncryptionCodec_RoundTrip(t *testing.T) {
+	codec, err := NewEncryptionCodec(newTestKeyring(t, "key-1"))
+	require.NoError(t, err)
+	dataConverter := converter.NewCodecDataConverter(converter.GetDefaultDataConverter(), codec)
+
+	payload, err := dataConverter.ToPayload("555-55-5555")
+	require.NoError(t, err)
+	require.Equal(t, MetadataEncodingEncrypted, string(payload.Metadata[converter.MetadataEncoding]))
+	require.Equal(t, "key-1", string(payload.Metadata[MetadataEncryptionKeyID]))
+	require.NotContains(t, string(payload.Data), "555-55-5555")
+
+	var ssn string
+	require.NoError(t, dataConverter.FromPayload(payload, &ssn))
+	require.Equal(t, "555-55-5555", ssn)
+}
+
+func Test_EncryptionCodec_PassesThroughUnencryptedPayloads(t *testing.T) {
+	codec, err := NewEncryptionCodec(newTestKeyring(t, "key-1"))
+	require.NoError(t, err)
+	plain, err := converter.GetDefaultDataConverter().ToPayload("hello")
+	require.NoError(t, err)
+	decoded, err := codec.Decode([]*commonpb.Payload{plain})
+	require.NoError(t, err)
+	require.Equal(t, plain, decoded[0])
+}
+
+func Test_EncryptionCodec_KeyRotation(t *testing.T) {
+	keyring := newTestKeyring(t, "key-1")
+	oldCodec, err := NewEncryptionCodec(keyring)
+	require.NoError(t, err)
+	plain, err := converter.GetDefaultDataConverter().ToPayload("555-55-5555")
+	require.NoError(t, err)
+	oldPayloads, err := oldCodec.Encode([]*commonpb.Payload{plain})
+	require.NoError(t, err)
+
+	// Rotate: key-2 encrypts new Payloads, key-1 still decrypts old ones.
+	require.NoError(t, keyring.Rotate("key-2"))
+	path := filepath.Join(t.TempDir(), "keys.json")
+	require.NoError(t, keyring.Save(path))
+	loaded, err := LoadKeyring(path)
+	require.NoError(t, err)
+	require.Equal(t, keyring, loaded)
+	newCodec, err := NewEncryptionCodec(loaded)
+	require.NoError(t, err)
+
+	decoded, err := newCodec.Decode(oldPayloads)
+	require.NoError(t, err)
+	require.Equal(t, plain.Data, decoded[0].Data)
+
+	newPayloads, err := newCodec.Encode([]*commonpb.Payload{plain})
+	require.NoError(t, err)
+	require.Equal(t, "key-2", string(newPayloads[0].Metadata[MetadataEncryptionKeyID]))
+
+	// Once key-1 is retired, its Payloads can no longer be decrypted.
+	delete(loaded.Keys, "key-1")
+	retiredCodec, err := NewEncryptionCodec(loaded)
+	require.NoError(t, err)
+	_, err = retiredCodec.Decode(oldPayloads)
+	require.ErrorContains(t, err, `no decryption key "key-1"`)
+	decoded, err = retiredCodec.Decode(newPayloads)
+	require.NoError(t, err)
+	require.Equal(t, plain.Data, decoded[0].Data)
+}
+
+func Test_EncryptionCodec_RejectsTamperedPayloads(t *testing.T) {
+	codec, err := NewEncryptionCodec(newTestKeyring(t, "key-1"))
+	require.NoError(t, err)
+	plain, err := converter.GetDefaultDataConverter().ToPayload("555-55-5555")
+	require.NoError(t, err)
+	encoded, err := codec.Encode([]*commonpb.Payload{plain})
+	require.NoError(t, err)
+	encoded[0].Data[len(encoded[0].Data)-1] ^= 0xff
+	_, err = codec.Decode(encoded)
+	require.Error(t, err)
+}
+
+func Test_NewDataConverter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	t.Setenv(KeyfileEnvVar, path)
+	_, err := NewDataConverter()
+	require.Error(t, err)
+
+	require.NoError(t, newTestKeyring(t, "key-1").Save(path))
+	dataConverter, err := NewDataConverter()
+	require.NoError(t, err)
+	payload, err := dataConverter.ToPayload("hello")
+	require.NoError(t, err)
+	require.Equal(t, MetadataEncodingEncrypted, string(payload.Metadata[converter.MetadataEncoding]))
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io/fs"
+	"log"
+	"time"
+
+	"documentation-samples-go/codec"
+)
+
+// keygen creates the keyfile, or adds a new active key to an existing keyfile.
+// Old keys are kept so that Payloads encrypted with them can still be decrypted.
+func main() {
+	keyfile := flag.String("keyfile", codec.KeyfilePath(), "path of the keyfile")
+	keyID := flag.String("key-id", time.Now().UTC().Format("20060102T150405Z"), "id of the new key")
+	flag.Parse()
+
+	keyring, err := codec.LoadKeyring(*keyfile)
+	if errors.Is(err, fs.ErrNotExist) {
+		keyring = &codec.Keyring{}
+	} else if err != nil {
+		log.Fatalln("Unable to load keyfile", err)
+	}
+	err = keyring.Rotate(*keyID)
+	if err != nil {
+		log.Fatalln("Unable to add key", err)
+	}
+	err = keyring.Save(*keyfile)
+	if err != nil {
+		log.Fatalln("Unable to save keyfile", err)
+	}
+	log.Printf("Active key is now %q in %s (%d keys)\n", keyring.ActiveKeyID, *keyfile, len(keyring.Keys))
+}
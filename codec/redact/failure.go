@@ -0,0 +1,57 @@
+package redact
+
+import (
+	"regexp"
+
+	failurepb "go.temporal.io/api/failure/v1"
+	"go.temporal.io/sdk/converter"
+)
+
+/*
+Errors from other services may contain sensitive values as plain strings, where a masking type can't help.
+The FailureConverter replaces every match of its patterns in the message and stack trace of a Failure, and of each of its causes,
+before the Failure is recorded in the Event History.
+Set it in `client.Options.FailureConverter`.
+*/
+
+// Redacted replaces each match of a FailureConverter pattern.
+const Redacted = "[REDACTED]"
+
+// SSNPattern matches Social Security Numbers written as 555-55-5555.
+var SSNPattern = regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)
+
+// FailureConverter is a converter.FailureConverter that scrubs sensitive values from Failures.
+type FailureConverter struct {
+	parent   converter.FailureConverter
+	patterns []*regexp.Regexp
+}
+
+var _ converter.FailureConverter = (*FailureConverter)(nil)
+
+// NewFailureConverter returns a FailureConverter that converts errors with parent
+// and then replaces every match of patterns with Redacted.
+func NewFailureConverter(parent converter.FailureConverter, patterns ...*regexp.Regexp) *FailureConverter {
+	return &FailureConverter{parent: parent, patterns: patterns}
+}
+
+// ErrorToFailure implements converter.FailureConverter.
+func (c *FailureConverter) ErrorToFailure(err error) *failurepb.Failure {
+	failure := c.parent.ErrorToFailure(err)
+	for f := failure; f != nil; f = f.Cause {
+		f.Message = c.scrub(f.Message)
+		f.StackTrace = c.scrub(f.StackTrace)
+	}
+	return failure
+}
+
+// FailureToError implements converter.FailureConverter.
+func (c *FailureConverter) FailureToError(failure *failurepb.Failure) error {
+	return c.parent.FailureToError(failure)
+}
+
+func (c *FailureConverter) scrub(s string) string {
+	for _, pattern := range c.patterns {
+		s = pattern.ReplaceAllString(s, Redacted)
+	}
+	return s
+}
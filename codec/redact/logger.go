@@ -0,0 +1,81 @@
+package redact
+
+import (
+	"fmt"
+	stdlog "log"
+	"regexp"
+
+	"go.temporal.io/sdk/log"
+)
+
+/*
+The SDK logs Activity and Workflow errors as they happen, before any FailureConverter sees them.
+Wrap the Client logger with NewLogger to scrub the same patterns from log lines.
+*/
+
+type logger struct {
+	parent   log.Logger
+	patterns []*regexp.Regexp
+}
+
+// NewLogger returns a log.Logger that replaces every match of patterns with Redacted
+// in the message and values of each log line before passing it to parent.
+// If parent is nil, lines are written with the standard library log package.
+func NewLogger(parent log.Logger, patterns ...*regexp.Regexp) log.Logger {
+	if parent == nil {
+		parent = stdLogger{}
+	}
+	return &logger{parent: parent, patterns: patterns}
+}
+
+func (l *logger) Debug(msg string, keyvals ...interface{}) {
+	l.parent.Debug(l.scrub(msg), l.scrubKeyvals(keyvals)...)
+}
+
+func (l *logger) Info(msg string, keyvals ...interface{}) {
+	l.parent.Info(l.scrub(msg), l.scrubKeyvals(keyvals)...)
+}
+
+func (l *logger) Warn(msg string, keyvals ...interface{}) {
+	l.parent.Warn(l.scrub(msg), l.scrubKeyvals(keyvals)...)
+}
+
+func (l *logger) Error(msg string, keyvals ...interface{}) {
+	l.parent.Error(l.scrub(msg), l.scrubKeyvals(keyvals)...)
+}
+
+func (l *logger) scrub(s string) string {
+	for _, pattern := range l.patterns {
+		s = pattern.ReplaceAllString(s, Redacted)
+	}
+	return s
+}
+
+// scrubKeyvals replaces values, such as errors, whose text contains a match with the scrubbed text.
+// Other values are passed through unchanged.
+func (l *logger) scrubKeyvals(keyvals []interface{}) []interface{} {
+	result := make([]interface{}, len(keyvals))
+	for i, v := range keyvals {
+		result[i] = v
+		if v == nil {
+			continue
+		}
+		s := fmt.Sprint(v)
+		if scrubbed := l.scrub(s); scrubbed != s {
+			result[i] = scrubbed
+		}
+	}
+	return result
+}
+
+// stdLogger writes log lines in the same format as the SDK default logger.
+type stdLogger struct{}
+
+func (l stdLogger) Debug(msg string, keyvals ...interface{}) { l.println("DEBUG", msg, keyvals) }
+func (l stdLogger) Info(msg string, keyvals ...interface{})  { l.println("INFO ", msg, keyvals) }
+func (l stdLogger) Warn(msg string, keyvals ...interface{})  { l.println("WARN ", msg, keyvals) }
+func (l stdLogger) Error(msg string, keyvals ...interface{}) { l.println("ERROR", msg, keyvals) }
+
+func (stdLogger) println(level, msg string, keyvals []interface{}) {
+	stdlog.Println(append([]interface{}{level, msg}, keyvals...)...)
+}
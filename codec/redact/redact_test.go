@@ -0,0 +1,56 @@
+package redact
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+)
+
+const rawSSN = "555-55-1234"
+
+func Test_SSN_Masks(t *testing.T) {
+	ssn := SSN(rawSSN)
+	require.Equal(t, "***-**-1234", ssn.String())
+	for _, verb := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x", "%10s"} {
+		require.NotContains(t, fmt.Sprintf(verb, ssn), rawSSN, verb)
+	}
+	require.NotContains(t, fmt.Sprint(struct{ SSN SSN }{ssn}), rawSSN)
+	require.Equal(t, "***-**-****", SSN("12").String())
+	require.Equal(t, rawSSN, ssn.Reveal())
+}
+
+func Test_FailureConverter_Scrubs(t *testing.T) {
+	failureConverter := NewFailureConverter(temporal.GetDefaultFailureConverter(), SSNPattern)
+	err := temporal.NewApplicationErrorWithCause("trace service rejected "+rawSSN, "TraceError",
+		errors.New("lookup "+rawSSN+" failed"))
+
+	failure := failureConverter.ErrorToFailure(err)
+	require.Equal(t, "trace service rejected "+Redacted, failure.Message)
+	require.Equal(t, "lookup "+Redacted+" failed", failure.Cause.Message)
+	require.NotContains(t, failure.String(), rawSSN)
+
+	var applicationErr *temporal.ApplicationError
+	require.True(t, errors.As(failureConverter.FailureToError(failure), &applicationErr))
+	require.Equal(t, "TraceError", applicationErr.Type())
+}
+
+// lineLogger keeps the last log line.
+type lineLogger struct{ line string }
+
+func (l *lineLogger) Debug(msg string, keyvals ...interface{}) { l.Info(msg, keyvals...) }
+func (l *lineLogger) Info(msg string, keyvals ...interface{}) {
+	l.line = fmt.Sprintln(append([]interface{}{msg}, keyvals...)...)
+}
+func (l *lineLogger) Warn(msg string, keyvals ...interface{})  { l.Info(msg, keyvals...) }
+func (l *lineLogger) Error(msg string, keyvals ...interface{}) { l.Info(msg, keyvals...) }
+
+func Test_Logger_Scrubs(t *testing.T) {
+	parent := &lineLogger{}
+	logger := NewLogger(parent, SSNPattern)
+
+	logger.Error("Activity error. "+rawSSN, "Attempt", 1, "Error", errors.New("trace service rejected "+rawSSN), "SSN", SSN(rawSSN))
+	require.Equal(t, "Activity error. [REDACTED] Attempt 1 Error trace service rejected [REDACTED] SSN ***-**-1234\n", parent.line)
+}
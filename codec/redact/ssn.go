@@ -0,0 +1,55 @@
+// Package redact keeps sensitive values, such as Social Security Numbers, out of logs and failures.
+package redact
+
+import (
+	"fmt"
+	"io"
+)
+
+/*
+Workflow and Activity parameters often end up in log lines and error messages.
+Give sensitive parameters a type that masks itself, so that printing or logging one never shows the raw value:
+
+- `String()`, and every `fmt` verb, return the masked value.
+- zap logs Stringers with `String()`, and `log/slog` uses `LogValue()`, so JSON logs are masked too.
+
+The SSN is still serialized unmasked in Payloads, because Activities need the raw value.
+The Encryption Codec encrypts those Payloads.
+Call `Reveal()` only where the raw value is needed.
+*/
+
+// SSN is a Social Security Number that masks itself when printed or logged.
+type SSN string
+
+// Reveal returns the raw SSN.
+func (s SSN) Reveal() string {
+	return string(s)
+}
+
+// String returns the SSN with all but the last four digits masked.
+func (s SSN) String() string {
+	if len(s) < 4 {
+		return "***-**-****"
+	}
+	return "***-**-" + string(s[len(s)-4:])
+}
+
+// GoString masks the SSN for the %#v verb.
+func (s SSN) GoString() string {
+	return "redact.SSN(" + s.String() + ")"
+}
+
+// Format masks the SSN for every fmt verb, such as %v, %s, %q and %x.
+func (s SSN) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if f.Flag('#') {
+			io.WriteString(f, s.GoString())
+			return
+		}
+	case 'q':
+		fmt.Fprintf(f, "%q", s.String())
+		return
+	}
+	io.WriteString(f, s.String())
+}
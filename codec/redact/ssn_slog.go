@@ -0,0 +1,10 @@
+//go:build go1.21
+
+package redact
+
+import "log/slog"
+
+// LogValue masks the SSN in log/slog output, including JSON logs.
+func (s SSN) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
@@ -0,0 +1,18 @@
+//go:build go1.21
+
+package redact
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SSN_MasksJSONLogs(t *testing.T) {
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("Running SSN trace", "SSN", SSN(rawSSN))
+	require.Contains(t, buf.String(), `"SSN":"***-**-1234"`)
+	require.NotContains(t, buf.String(), rawSSN)
+}
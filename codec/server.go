@@ -0,0 +1,98 @@
+package codec
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"go.temporal.io/sdk/converter"
+)
+
+/*
+The Temporal Web UI and CLI can't show encrypted Payloads on their own.
+Point them at a codec server, which implements the remote codec protocol:
+`POST /encode` and `POST /decode` with a JSON body of the form `{"payloads": [...]}`.
+
+The Web UI calls the codec server from the browser, so the server must allow the UI origin with CORS.
+When a bearer token is set, every request except the CORS preflight must carry it in the `Authorization` header.
+*/
+
+// ServerOptions are options for NewServer.
+type ServerOptions struct {
+	// AllowedOrigins are the origins, such as the Web UI at http://localhost:8233, allowed to call the server from a browser.
+	// The origins listed may send credentials. "*" allows any other origin, but without credentials.
+	AllowedOrigins []string
+	// BearerToken, if set, is required in the Authorization header of each request.
+	BearerToken string
+}
+
+type server struct {
+	codec   http.Handler
+	options ServerOptions
+}
+
+// NewServer returns an http.Handler that encodes and decodes Payloads with codecs.
+func NewServer(codecs []converter.PayloadCodec, options ServerOptions) http.Handler {
+	return &server{
+		codec:   converter.NewPayloadCodecHTTPHandler(codecs...),
+		options: options,
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		w.Header().Add("Vary", "Origin")
+		switch s.allowOrigin(origin) {
+		case originListed:
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		case originAny:
+			// Browsers refuse credentials with a wildcard, and echoing the origin instead would let any site send them.
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		}
+	}
+	if r.Method == http.MethodOptions {
+		// The browser sends a preflight request without credentials.
+		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, X-Namespace")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if !s.authorized(r) {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	s.codec.ServeHTTP(w, r)
+}
+
+type originAccess int
+
+const (
+	originDenied originAccess = iota
+	// originAny is allowed by "*", without credentials.
+	originAny
+	// originListed is listed in AllowedOrigins, and may send credentials.
+	originListed
+)
+
+func (s *server) allowOrigin(origin string) originAccess {
+	access := originDenied
+	for _, allowed := range s.options.AllowedOrigins {
+		if strings.EqualFold(allowed, origin) {
+			return originListed
+		}
+		if allowed == "*" {
+			access = originAny
+		}
+	}
+	return access
+}
+
+func (s *server) authorized(r *http.Request) bool {
+	if s.options.BearerToken == "" {
+		return true
+	}
+	want := "Bearer " + s.options.BearerToken
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte(want)) == 1
+}
@@ -0,0 +1,114 @@
+package codec
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+)
+
+const testOrigin = "http://localhost:8233"
+
+func newTestServer(t *testing.T, bearerToken string) *httptest.Server {
+	codec, err := NewEncryptionCodec(newTestKeyring(t, "key-1"))
+	require.NoError(t, err)
+	server := httptest.NewServer(NewServer([]converter.PayloadCodec{codec}, ServerOptions{
+		AllowedOrigins: []string{testOrigin},
+		BearerToken:    bearerToken,
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// post sends payloads to the codec server endpoint, such as /decode.
+func post(t *testing.T, url string, payloads []*commonpb.Payload, header http.Header) *http.Response {
+	var body bytes.Buffer
+	require.NoError(t, (&jsonpb.Marshaler{}).Marshal(&body, &commonpb.Payloads{Payloads: payloads}))
+	request, err := http.NewRequest(http.MethodPost, url, &body)
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", "application/json")
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	t.Cleanup(func() { response.Body.Close() })
+	return response
+}
+
+func readPayloads(t *testing.T, response *http.Response) []*commonpb.Payload {
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	var payloads commonpb.Payloads
+	require.NoError(t, jsonpb.Unmarshal(response.Body, &payloads))
+	return payloads.Payloads
+}
+
+func Test_Server_EncodeDecode(t *testing.T) {
+	server := newTestServer(t, "")
+	plain, err := converter.GetDefaultDataConverter().ToPayload("555-55-5555")
+	require.NoError(t, err)
+
+	encoded := readPayloads(t, post(t, server.URL+"/encode", []*commonpb.Payload{plain}, nil))
+	require.Equal(t, MetadataEncodingEncrypted, string(encoded[0].Metadata[converter.MetadataEncoding]))
+
+	decoded := readPayloads(t, post(t, server.URL+"/decode", encoded, nil))
+	require.True(t, plain.Equal(decoded[0]))
+}
+
+func Test_Server_CORS(t *testing.T) {
+	server := newTestServer(t, "your-token")
+
+	// The preflight request carries no credentials.
+	request, err := http.NewRequest(http.MethodOptions, server.URL+"/decode", nil)
+	require.NoError(t, err)
+	request.Header.Set("Origin", testOrigin)
+	request.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Equal(t, testOrigin, response.Header.Get("Access-Control-Allow-Origin"))
+	require.Equal(t, "true", response.Header.Get("Access-Control-Allow-Credentials"))
+	require.Contains(t, response.Header.Get("Access-Control-Allow-Headers"), "X-Namespace")
+
+	// Other origins are not allowed.
+	response = post(t, server.URL+"/decode", nil, http.Header{
+		"Origin":        {"https://example.com"},
+		"Authorization": {"Bearer your-token"},
+	})
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Empty(t, response.Header.Get("Access-Control-Allow-Origin"))
+}
+
+func Test_Server_CORSWildcard(t *testing.T) {
+	server := httptest.NewServer(NewServer(nil, ServerOptions{AllowedOrigins: []string{testOrigin, "*"}}))
+	t.Cleanup(server.Close)
+
+	// Any origin is allowed, but not with credentials.
+	response := post(t, server.URL+"/decode", nil, http.Header{"Origin": {"https://example.com"}})
+	require.Equal(t, "*", response.Header.Get("Access-Control-Allow-Origin"))
+	require.Empty(t, response.Header.Get("Access-Control-Allow-Credentials"))
+
+	// The origins listed still may send credentials.
+	response = post(t, server.URL+"/decode", nil, http.Header{"Origin": {testOrigin}})
+	require.Equal(t, testOrigin, response.Header.Get("Access-Control-Allow-Origin"))
+	require.Equal(t, "true", response.Header.Get("Access-Control-Allow-Credentials"))
+}
+
+func Test_Server_BearerToken(t *testing.T) {
+	server := newTestServer(t, "your-token")
+
+	response := post(t, server.URL+"/decode", nil, nil)
+	require.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	response = post(t, server.URL+"/decode", nil, http.Header{"Authorization": {"Bearer wrong-token"}})
+	require.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	response = post(t, server.URL+"/decode", nil, http.Header{"Authorization": {"Bearer your-token"}})
+	require.Equal(t, http.StatusOK, response.StatusCode)
+}
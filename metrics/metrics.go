@@ -0,0 +1,91 @@
+// Package metrics wires the Temporal Go SDK metrics into a Prometheus scrape endpoint.
+package metrics
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/uber-go/tally/v4"
+	"github.com/uber-go/tally/v4/prometheus"
+	"go.temporal.io/sdk/client"
+	sdktally "go.temporal.io/sdk/contrib/tally"
+)
+
+/*
+The Temporal Go SDK emits Worker and Client metrics, such as Task latency, poller state and Schedule-To-Start latency, through the `MetricsHandler` set in `client.Options`.
+If no handler is set, the metrics are discarded.
+
+The handler below is backed by a [Tally](https://github.com/uber-go/tally) scope that reports to Prometheus.
+Set it on the Client, then serve `/metrics` so that Prometheus can scrape the Worker Process:
+
+	m, err := metrics.New()
+	...
+	temporalClient, err := client.Dial(client.Options{MetricsHandler: m.Handler()})
+	...
+	go m.ListenAndServe(":9090")
+*/
+
+// ReportInterval is how often the Tally scope flushes metrics to the Prometheus registry.
+const ReportInterval = time.Second
+
+// Metrics holds the SDK MetricsHandler and the Prometheus registry it reports to.
+type Metrics struct {
+	handler  client.MetricsHandler
+	reporter prometheus.Reporter
+	closer   io.Closer
+}
+
+// New returns Metrics that report to a new Prometheus registry.
+func New() (*Metrics, error) {
+	registry := prom.NewRegistry()
+	reporter := prometheus.NewReporter(prometheus.Options{
+		Registerer: registry,
+		Gatherer:   registry,
+		// Report timers, such as Task latency, as histograms so that they can be aggregated.
+		DefaultTimerType: prometheus.HistogramTimerType,
+		OnRegisterError: func(err error) {
+			log.Println("Unable to register metric", err)
+		},
+	})
+	scope, closer := tally.NewRootScope(tally.ScopeOptions{
+		CachedReporter:  reporter,
+		Separator:       prometheus.DefaultSeparator,
+		SanitizeOptions: &sdktally.PrometheusSanitizeOptions,
+	}, ReportInterval)
+	if scope == nil {
+		return nil, errors.New("unable to create metrics scope")
+	}
+	// Use Prometheus naming conventions, such as the _seconds suffix on timers.
+	scope = sdktally.NewPrometheusNamingScope(scope)
+	return &Metrics{
+		handler:  sdktally.NewMetricsHandler(scope),
+		reporter: reporter,
+		closer:   closer,
+	}, nil
+}
+
+// Handler returns the MetricsHandler to set in client.Options.
+func (m *Metrics) Handler() client.MetricsHandler {
+	return m.handler
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.reporter.HTTPHandler().ServeHTTP(w, r)
+}
+
+// ListenAndServe serves /metrics on addr until the server fails.
+func (m *Metrics) ListenAndServe(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m)
+	return http.ListenAndServe(addr, mux)
+}
+
+// Close flushes any buffered metrics and stops reporting.
+func (m *Metrics) Close() error {
+	return m.closer.Close()
+}
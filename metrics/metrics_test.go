@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Metrics(t *testing.T) {
+	m, err := New()
+	require.NoError(t, err)
+	m.Handler().WithTags(map[string]string{"activity_type": "YourActivityDefinition"}).
+		Counter("your_activity_executions").Inc(2)
+	// Close flushes the counter to the Prometheus registry.
+	require.NoError(t, m.Close())
+
+	server := httptest.NewServer(m)
+	defer server.Close()
+	resp, err := server.Client().Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), `your_activity_executions_total{activity_type="YourActivityDefinition"} 2`)
+}
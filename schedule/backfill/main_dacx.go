@@ -8,15 +8,23 @@ import (
 	"github.com/temporalio/documentation-samples-go/schedule"
 	"go.temporal.io/api/enums/v1"
 	"go.temporal.io/sdk/client"
+
+	"documentation-samples-go/codec"
 )
 
 func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		log.Fatalln("Unable to create Data Converter", err)
+	}
 	// Create a Workflow to backfill
 	temporalClient, err := client.Dial(client.Options{
-		HostPort: client.DefaultHostPort,
+		HostPort:      client.DefaultHostPort,
+		DataConverter: dataConverter,
 	})
 	if err != nil {
 		log.Fatalln("Unable to create Temporal Client", err)
@@ -81,5 +89,5 @@ tags:
 - code sample
 - schedule
 - backfill
-lines: 13, 46-62, 64, 66-72
+lines: 15, 54-70, 72, 74-80
 @dacx */
@@ -0,0 +1,311 @@
+// Package bulk pauses and unpauses many Schedules at once, such as every Schedule of a service during a deploy.
+//
+// Pause pauses the Schedules that a Selector matches and returns a Snapshot of what it did: the Schedules it paused,
+// and those that were already paused, which it leaves as they are. Unpause takes the Snapshot and unpauses only the
+// Schedules that Pause paused and that are still paused with its note, and gives them back the notes they had before.
+package bulk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/temporal"
+)
+
+// Selector matches Schedules by Id prefix, memo and Search Attributes.
+// A Schedule matches when every field does, and an empty field matches every Schedule.
+type Selector struct {
+	Prefix string
+	// Memo and SearchAttributes map keys to the value a Schedule must have.
+	// A value matches an equal value, or a list that contains it. Numbers are float64, as encoding/json decodes them.
+	Memo             map[string]interface{}
+	SearchAttributes map[string]interface{}
+}
+
+// Empty reports whether the Selector matches every Schedule.
+func (s Selector) Empty() bool {
+	return s.Prefix == "" && len(s.Memo) == 0 && len(s.SearchAttributes) == 0
+}
+
+// Match reports whether the listed Schedule entry matches the Selector.
+func (s Selector) Match(entry *client.ScheduleListEntry) (bool, error) {
+	if !strings.HasPrefix(entry.ID, s.Prefix) {
+		return false, nil
+	}
+	ok, err := matchFields(s.Memo, entry.Memo.GetFields())
+	if err != nil || !ok {
+		return false, err
+	}
+	return matchFields(s.SearchAttributes, entry.SearchAttributes.GetIndexedFields())
+}
+
+func matchFields(want map[string]interface{}, fields map[string]*commonpb.Payload) (bool, error) {
+	for key, value := range want {
+		payload, ok := fields[key]
+		if !ok {
+			return false, nil
+		}
+		// The memo and Search Attributes of a Schedule are not encoded by custom Data Converters.
+		var got interface{}
+		if err := converter.GetDefaultDataConverter().FromPayload(payload, &got); err != nil {
+			return false, fmt.Errorf("unable to decode %s: %w", key, err)
+		}
+		if !matchValue(value, got) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func matchValue(want, got interface{}) bool {
+	if reflect.DeepEqual(want, got) {
+		return true
+	}
+	list, _ := got.([]interface{})
+	for _, v := range list {
+		if reflect.DeepEqual(want, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Snapshot records a bulk pause.
+type Snapshot struct {
+	Time time.Time `json:"time"`
+	// Note is the note that Pause gave the Schedules it paused.
+	Note string `json:"note"`
+	// Paused are the Ids of the Schedules that Pause paused.
+	Paused []string `json:"paused"`
+	// PreviousNotes maps the Ids of Paused to the notes the Schedules had before Pause paused them.
+	PreviousNotes map[string]string `json:"previousNotes"`
+	// AlreadyPaused are the matched Schedules that were paused before, which Pause and Unpause leave paused.
+	AlreadyPaused []Skipped `json:"alreadyPaused"`
+}
+
+// Skipped is a Schedule that a bulk operation left as it is, with the reason.
+type Skipped struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// UnpauseResult is what Unpause did with the Schedules of a Snapshot.
+type UnpauseResult struct {
+	Unpaused []string  `json:"unpaused"`
+	Skipped  []Skipped `json:"skipped"`
+}
+
+// Pauser pauses and unpauses Schedules.
+type Pauser struct {
+	Schedules client.ScheduleClient
+	// By names who pauses and unpauses. It goes in the notes of the Schedules.
+	By string
+	// DryRun reports what Pause and Unpause would do without changing any Schedule.
+	DryRun bool
+	// Checkpoint, if set, saves the Snapshot of Pause before it pauses anything and after each Schedule it pauses.
+	// Pause stops when Checkpoint fails.
+	Checkpoint func(snap *Snapshot) error
+	// Now returns the time of a Snapshot. It defaults to time.Now.
+	Now func() time.Time
+}
+
+// PauseNote returns the note of a Schedule that by paused for reason.
+func PauseNote(by, reason string) string {
+	return fmt.Sprintf("Paused by %s: %s", by, reason)
+}
+
+// Select lists the Schedules of the Namespace and returns the sorted Ids of those that sel matches.
+// The list comes from Visibility, so a Schedule created a moment ago may not be in it yet.
+func (p *Pauser) Select(ctx context.Context, sel Selector) ([]string, error) {
+	iter, err := p.Schedules.List(ctx, client.ScheduleListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list schedules: %w", err)
+	}
+	var ids []string
+	for iter.HasNext() {
+		entry, err := iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("unable to list schedules: %w", err)
+		}
+		ok, err := sel.Match(entry)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %s: %w", entry.ID, err)
+		}
+		if ok {
+			ids = append(ids, entry.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Pause pauses the Schedules ids that are not paused yet, with the PauseNote of p.By and reason.
+// The paused state comes from Describe rather than from the list, which may be out of date, and Schedules deleted since
+// they were listed are left out. Pause stops at the first Schedule it fails to pause, and returns the Snapshot of what
+// it did even then, so that the Schedules it paused can still be unpaused.
+func (p *Pauser) Pause(ctx context.Context, ids []string, reason string) (*Snapshot, error) {
+	now := time.Now
+	if p.Now != nil {
+		now = p.Now
+	}
+	snap := &Snapshot{
+		Time:          now(),
+		Note:          PauseNote(p.By, reason),
+		Paused:        []string{},
+		PreviousNotes: map[string]string{},
+		AlreadyPaused: []Skipped{},
+	}
+	if err := p.checkpoint(snap); err != nil {
+		return snap, err
+	}
+	for _, id := range ids {
+		handle := p.Schedules.GetHandle(ctx, id)
+		desc, err := handle.Describe(ctx)
+		var notFound *serviceerror.NotFound
+		if errors.As(err, &notFound) {
+			continue
+		}
+		if err != nil {
+			return snap, fmt.Errorf("unable to describe schedule %s: %w", id, err)
+		}
+		if state := desc.Schedule.State; state != nil && state.Paused {
+			snap.AlreadyPaused = append(snap.AlreadyPaused, Skipped{ID: id, Reason: state.Note})
+			continue
+		}
+		if !p.DryRun {
+			if err := handle.Pause(ctx, client.SchedulePauseOptions{Note: snap.Note}); err != nil {
+				return snap, fmt.Errorf("unable to pause schedule %s: %w", id, err)
+			}
+		}
+		snap.Paused = append(snap.Paused, id)
+		if state := desc.Schedule.State; state != nil {
+			snap.PreviousNotes[id] = state.Note
+		}
+		if err := p.checkpoint(snap); err != nil {
+			return snap, fmt.Errorf("paused schedule %s but %w; the schedules paused are: %s", id, err, strings.Join(snap.Paused, ", "))
+		}
+	}
+	return snap, p.checkpoint(snap)
+}
+
+func (p *Pauser) checkpoint(snap *Snapshot) error {
+	if p.Checkpoint == nil || p.DryRun {
+		return nil
+	}
+	return p.Checkpoint(snap)
+}
+
+// Unpause unpauses the Schedules that snap records as paused, and gives them back the notes they had before Pause,
+// so that their state is as it was. It skips the Schedules that are no longer paused, were deleted, or have a note
+// other than the one Pause gave them, since someone else paused them since.
+// Unpause stops at the first Schedule it fails to unpause, and can be run again.
+func (p *Pauser) Unpause(ctx context.Context, snap *Snapshot) (*UnpauseResult, error) {
+	result := &UnpauseResult{Unpaused: []string{}, Skipped: []Skipped{}}
+	for _, id := range snap.Paused {
+		var skipped string
+		// Unpause with an empty note would set a default note, so the note is restored with an update instead.
+		err := p.Schedules.GetHandle(ctx, id).Update(ctx, client.ScheduleUpdateOptions{
+			DoUpdate: func(input client.ScheduleUpdateInput) (*client.ScheduleUpdate, error) {
+				s := input.Description.Schedule
+				switch {
+				case s.State == nil || !s.State.Paused:
+					skipped = "not paused"
+				case s.State.Note != snap.Note:
+					skipped = fmt.Sprintf("paused again with note %q", s.State.Note)
+				}
+				if skipped != "" || p.DryRun {
+					return nil, temporal.ErrSkipScheduleUpdate
+				}
+				state := *s.State
+				state.Paused = false
+				state.Note = snap.PreviousNotes[id]
+				s.State = &state
+				return &client.ScheduleUpdate{Schedule: &s}, nil
+			},
+		})
+		var notFound *serviceerror.NotFound
+		switch {
+		case errors.As(err, &notFound):
+			skipped = "deleted"
+		case err != nil:
+			return result, fmt.Errorf("unable to unpause schedule %s: %w", id, err)
+		}
+		if skipped != "" {
+			result.Skipped = append(result.Skipped, Skipped{ID: id, Reason: skipped})
+			continue
+		}
+		result.Unpaused = append(result.Unpaused, id)
+	}
+	return result, nil
+}
+
+// SnapshotFile is the file that a Snapshot is saved to while Pause runs.
+type SnapshotFile struct {
+	path string
+}
+
+// CreateSnapshotFile creates the empty file path for a Snapshot, so that a path that can't be written fails
+// before any Schedule is paused. It does not overwrite an existing file, which may be the only record of an earlier pause.
+func CreateSnapshotFile(path string) (*SnapshotFile, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create snapshot: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("unable to create snapshot: %w", err)
+	}
+	return &SnapshotFile{path: path}, nil
+}
+
+// Save replaces the content of the file with snap as JSON. It can be used as the Checkpoint of a Pauser.
+// It writes a temporary file next to it and renames it, so that the file always holds a whole Snapshot.
+func (f *SnapshotFile) Save(snap *Snapshot) error {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to save snapshot %s: %w", f.path, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*")
+	if err != nil {
+		return fmt.Errorf("unable to save snapshot %s: %w", f.path, err)
+	}
+	_, err = tmp.Write(append(data, '\n'))
+	if err == nil {
+		err = tmp.Chmod(0o644)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), f.path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to save snapshot %s: %w", f.path, err)
+	}
+	return nil
+}
+
+// LoadSnapshot reads a Snapshot that SnapshotFile.Save wrote.
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load snapshot: %w", err)
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("unable to load snapshot %s: %w", path, err)
+	}
+	return &snap, nil
+}
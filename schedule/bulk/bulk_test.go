@@ -0,0 +1,204 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/client"
+
+	"github.com/temporalio/documentation-samples-go/schedule/internal/scheduletest"
+)
+
+// newFake returns a ScheduleClient with the Schedules of the tests, and no calls recorded.
+func newFake(t *testing.T) *scheduletest.ScheduleClient {
+	fake := scheduletest.NewScheduleClient()
+	action := &client.ScheduleWorkflowAction{Workflow: "ScheduleWorkflow", TaskQueue: "schedule"}
+	for _, options := range []client.ScheduleOptions{
+		{ID: "billing-invoices", Memo: map[string]interface{}{"team": "billing"}},
+		{ID: "billing-reports", Paused: true, Note: "broken upstream", Memo: map[string]interface{}{"team": "billing"}},
+		{ID: "billing-cleanup", Note: "nightly", SearchAttributes: map[string]interface{}{"Service": []string{"billing", "storage"}}},
+		{ID: "search-reindex", Memo: map[string]interface{}{"team": "search", "tier": 2}},
+	} {
+		options.Action = action
+		_, err := fake.Create(context.Background(), options)
+		require.NoError(t, err)
+	}
+	fake.Calls = nil
+	return fake
+}
+
+// state returns the paused state and note of the Schedule id.
+func state(fake *scheduletest.ScheduleClient, id string) (bool, string) {
+	s := fake.Schedules[id].Schedule.State
+	return s.Paused, s.Note
+}
+
+// failCall returns a Fail function of a ScheduleClient that fails the call given.
+func failCall(call string) func(string) error {
+	return func(c string) error {
+		if c == call {
+			return errors.New(c + " failed")
+		}
+		return nil
+	}
+}
+
+func TestSelect(t *testing.T) {
+	pauser := &Pauser{Schedules: newFake(t)}
+	tests := []struct {
+		name     string
+		selector Selector
+		want     []string
+	}{
+		{"prefix", Selector{Prefix: "billing-"}, []string{"billing-cleanup", "billing-invoices", "billing-reports"}},
+		{"memo", Selector{Memo: map[string]interface{}{"team": "billing"}}, []string{"billing-invoices", "billing-reports"}},
+		{"memo number", Selector{Memo: map[string]interface{}{"tier": float64(2)}}, []string{"search-reindex"}},
+		{"search attribute list", Selector{SearchAttributes: map[string]interface{}{"Service": "storage"}}, []string{"billing-cleanup"}},
+		{"all fields", Selector{Prefix: "billing-r", Memo: map[string]interface{}{"team": "billing"}}, []string{"billing-reports"}},
+		{"no match", Selector{Memo: map[string]interface{}{"team": "ops"}}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ids, err := pauser.Select(context.Background(), test.selector)
+			require.NoError(t, err)
+			require.Equal(t, test.want, ids)
+		})
+	}
+}
+
+func TestPauseAndUnpause(t *testing.T) {
+	ctx := context.Background()
+	fake := newFake(t)
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	pauser := &Pauser{Schedules: fake, By: "alice", Now: func() time.Time { return now }}
+
+	// The Schedule that was already paused keeps its note, and the deleted one is left out.
+	snap, err := pauser.Pause(ctx, []string{"billing-invoices", "billing-reports", "billing-deleted", "billing-cleanup"}, "deploy 42")
+	require.NoError(t, err)
+	require.Equal(t, &Snapshot{
+		Time:          now,
+		Note:          "Paused by alice: deploy 42",
+		Paused:        []string{"billing-invoices", "billing-cleanup"},
+		PreviousNotes: map[string]string{"billing-invoices": "", "billing-cleanup": "nightly"},
+		AlreadyPaused: []Skipped{{ID: "billing-reports", Reason: "broken upstream"}},
+	}, snap)
+	require.Equal(t, []string{"pause billing-invoices", "pause billing-cleanup"}, fake.Calls)
+
+	// Each Schedule gets back the note it had before, so the pause leaves no trace.
+	fake.Calls = nil
+	result, err := pauser.Unpause(ctx, snap)
+	require.NoError(t, err)
+	require.Equal(t, &UnpauseResult{Unpaused: []string{"billing-invoices", "billing-cleanup"}, Skipped: []Skipped{}}, result)
+	require.Equal(t, []string{"update billing-invoices", "update billing-cleanup"}, fake.Calls)
+	paused, note := state(fake, "billing-invoices")
+	require.False(t, paused)
+	require.Empty(t, note)
+	paused, note = state(fake, "billing-cleanup")
+	require.False(t, paused)
+	require.Equal(t, "nightly", note)
+
+	// Unpausing again skips what is no longer paused.
+	result, err = pauser.Unpause(ctx, snap)
+	require.NoError(t, err)
+	require.Empty(t, result.Unpaused)
+	require.Contains(t, result.Skipped, Skipped{ID: "billing-invoices", Reason: "not paused"})
+}
+
+func TestUnpauseSkipsSchedulesPausedAgain(t *testing.T) {
+	ctx := context.Background()
+	fake := newFake(t)
+	pauser := &Pauser{Schedules: fake, By: "alice"}
+	snap, err := pauser.Pause(ctx, []string{"billing-invoices", "billing-cleanup", "search-reindex"}, "deploy 42")
+	require.NoError(t, err)
+
+	// Someone else paused billing-cleanup again after it was unpaused by hand, and search-reindex was deleted.
+	fake.Schedules["billing-cleanup"].Schedule.State.Note = "Paused by bob: disk full"
+	delete(fake.Schedules, "search-reindex")
+	fake.Calls = nil
+	result, err := pauser.Unpause(ctx, snap)
+	require.NoError(t, err)
+	require.Equal(t, &UnpauseResult{
+		Unpaused: []string{"billing-invoices"},
+		Skipped: []Skipped{
+			{ID: "billing-cleanup", Reason: `paused again with note "Paused by bob: disk full"`},
+			{ID: "search-reindex", Reason: "deleted"},
+		},
+	}, result)
+	require.Equal(t, []string{"update billing-invoices"}, fake.Calls)
+	paused, _ := state(fake, "billing-cleanup")
+	require.True(t, paused)
+}
+
+func TestPauseSavesSnapshotFile(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	file, err := CreateSnapshotFile(path)
+	require.NoError(t, err)
+	// The file is never overwritten, and a path that can't be written fails before anything is paused.
+	_, err = CreateSnapshotFile(path)
+	require.ErrorIs(t, err, os.ErrExist)
+	_, err = CreateSnapshotFile(filepath.Join(path, "snapshot.json"))
+	require.Error(t, err)
+
+	// The file is saved before the first pause and after each one, so it holds whatever was paused before a failure.
+	fake := newFake(t)
+	fake.Fail = failCall("pause search-reindex")
+	var saved [][]string
+	pauser := &Pauser{Schedules: fake, By: "alice", Checkpoint: func(snap *Snapshot) error {
+		if err := file.Save(snap); err != nil {
+			return err
+		}
+		loaded, err := LoadSnapshot(path)
+		require.NoError(t, err)
+		saved = append(saved, loaded.Paused)
+		return nil
+	}}
+	_, err = pauser.Pause(ctx, []string{"billing-invoices", "billing-cleanup", "search-reindex"}, "deploy 42")
+	require.ErrorContains(t, err, "search-reindex")
+	require.Equal(t, [][]string{{}, {"billing-invoices"}, {"billing-invoices", "billing-cleanup"}}, saved)
+	loaded, err := LoadSnapshot(path)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"billing-invoices": "", "billing-cleanup": "nightly"}, loaded.PreviousNotes)
+}
+
+func TestPauseStopsWhenCheckpointFails(t *testing.T) {
+	fake := newFake(t)
+	saves := 0
+	pauser := &Pauser{Schedules: fake, By: "alice", Checkpoint: func(*Snapshot) error {
+		saves++
+		if saves == 2 {
+			return errors.New("disk full")
+		}
+		return nil
+	}}
+	_, err := pauser.Pause(context.Background(), []string{"billing-invoices", "billing-cleanup"}, "deploy")
+	require.ErrorContains(t, err, "paused schedule billing-invoices but disk full")
+	require.Equal(t, []string{"pause billing-invoices"}, fake.Calls)
+}
+
+func TestPauseDryRun(t *testing.T) {
+	fake := newFake(t)
+	pauser := &Pauser{Schedules: fake, By: "alice", DryRun: true}
+	snap, err := pauser.Pause(context.Background(), []string{"billing-invoices", "billing-reports"}, "deploy")
+	require.NoError(t, err)
+	require.Equal(t, []string{"billing-invoices"}, snap.Paused)
+	require.Empty(t, fake.Calls)
+	paused, _ := state(fake, "billing-invoices")
+	require.False(t, paused)
+}
+
+func TestPauseStopsAtFailure(t *testing.T) {
+	fake := newFake(t)
+	fake.Fail = failCall("pause billing-invoices")
+	pauser := &Pauser{Schedules: fake, By: "alice"}
+	snap, err := pauser.Pause(context.Background(), []string{"billing-cleanup", "billing-invoices", "search-reindex"}, "deploy")
+	require.ErrorContains(t, err, "billing-invoices")
+	require.Equal(t, []string{"billing-cleanup"}, snap.Paused, "the snapshot keeps the Schedules paused before the failure")
+	paused, _ := state(fake, "search-reindex")
+	require.False(t, paused)
+}
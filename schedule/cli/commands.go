@@ -0,0 +1,345 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/client"
+
+	"github.com/temporalio/documentation-samples-go/schedule"
+	"github.com/temporalio/documentation-samples-go/schedule/bulk"
+	"github.com/temporalio/documentation-samples-go/schedule/gaps"
+	"github.com/temporalio/documentation-samples-go/schedule/reconcile"
+)
+
+var commands = map[string]command{
+	"create":       {name: "create", summary: "Create a Schedule", setup: setupCreate},
+	"describe":     {name: "describe", summary: "Show a Schedule", setup: setupDescribe},
+	"list":         {name: "list", summary: "List the Schedules of the Namespace", setup: setupList},
+	"pause":        {name: "pause", summary: "Pause a Schedule", setup: setupPause},
+	"unpause":      {name: "unpause", summary: "Unpause a Schedule", setup: setupUnpause},
+	"bulk-pause":   {name: "bulk-pause", summary: "Pause the Schedules that match an Id prefix, memo or Search Attributes, and save a snapshot", setup: setupBulkPause},
+	"bulk-unpause": {name: "bulk-unpause", summary: "Unpause the Schedules that bulk-pause paused", setup: setupBulkUnpause},
+	"trigger":      {name: "trigger", summary: "Take the Action of a Schedule now", setup: setupTrigger},
+	"update":       {name: "update", summary: "Change the given fields of a Schedule", setup: setupUpdate},
+	"backfill":     {name: "backfill", summary: "Take the Actions a Schedule would have taken in a past time range", setup: setupBackfill},
+	"delete":       {name: "delete", summary: "Delete a Schedule", setup: setupDelete},
+	"plan":         {name: "plan", summary: "Show the changes that make the Schedules match schedule files", setup: setupPlan},
+	"apply":        {name: "apply", summary: "Make the Schedules match schedule files", setup: setupApply},
+	"preview":      {name: "preview", summary: "Show when a spec would take actions, without contacting the Temporal Service", setup: setupPreview, offline: true},
+	"fill-gaps":    {name: "fill-gaps", summary: "Backfill only the action times in a range that have no Workflow Execution", setup: setupFillGaps},
+}
+
+func setupCreate(fs *flag.FlagSet, o *options) func(ctx context.Context, e *env) error {
+	o.idFlag(fs)
+	o.specFlags(fs)
+	o.actionFlags(fs)
+	o.overlapFlag(fs)
+	o.noteFlag(fs, "Note about the Schedule")
+	o.pausedFlag(fs)
+	return func(ctx context.Context, e *env) error {
+		workflowID := o.workflowID
+		if workflowID == "" {
+			workflowID = o.id + "_workflow"
+		}
+		_, err := e.schedules.Create(ctx, client.ScheduleOptions{
+			ID:   o.id,
+			Spec: o.spec,
+			Action: &client.ScheduleWorkflowAction{
+				ID:        workflowID,
+				Workflow:  o.workflowType,
+				Args:      o.workflowArgs,
+				TaskQueue: o.taskQueue,
+			},
+			Overlap: o.overlapPolicy,
+			Note:    o.note,
+			Paused:  o.paused,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to create schedule %s: %w", o.id, err)
+		}
+		return e.printDone(o.id, "created")
+	}
+}
+
+func setupDescribe(fs *flag.FlagSet, o *options) func(ctx context.Context, e *env) error {
+	o.idFlag(fs)
+	return func(ctx context.Context, e *env) error {
+		desc, err := e.schedules.GetHandle(ctx, o.id).Describe(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to describe schedule %s: %w", o.id, err)
+		}
+		view, err := describeView(o.id, desc, e.dc)
+		if err != nil {
+			return err
+		}
+		return e.printSchedule(view)
+	}
+}
+
+func setupList(fs *flag.FlagSet, o *options) func(ctx context.Context, e *env) error {
+	fs.IntVar(&o.pageSize, "page-size", 100, "Number of Schedules to fetch per request")
+	return func(ctx context.Context, e *env) error {
+		iter, err := e.schedules.List(ctx, client.ScheduleListOptions{PageSize: o.pageSize})
+		if err != nil {
+			return fmt.Errorf("unable to list schedules: %w", err)
+		}
+		views := []scheduleView{}
+		for iter.HasNext() {
+			entry, err := iter.Next()
+			if err != nil {
+				return fmt.Errorf("unable to list schedules: %w", err)
+			}
+			views = append(views, listView(entry))
+		}
+		return e.printList(views)
+	}
+}
+
+func setupPause(fs *flag.FlagSet, o *options) func(ctx context.Context, e *env) error {
+	o.idFlag(fs)
+	o.noteFlag(fs, "Reason for pausing")
+	return func(ctx context.Context, e *env) error {
+		err := e.schedules.GetHandle(ctx, o.id).Pause(ctx, client.SchedulePauseOptions{Note: o.note})
+		if err != nil {
+			return fmt.Errorf("unable to pause schedule %s: %w", o.id, err)
+		}
+		return e.printDone(o.id, "paused")
+	}
+}
+
+func setupUnpause(fs *flag.FlagSet, o *options) func(ctx context.Context, e *env) error {
+	o.idFlag(fs)
+	o.noteFlag(fs, "Reason for unpausing")
+	return func(ctx context.Context, e *env) error {
+		err := e.schedules.GetHandle(ctx, o.id).Unpause(ctx, client.ScheduleUnpauseOptions{Note: o.note})
+		if err != nil {
+			return fmt.Errorf("unable to unpause schedule %s: %w", o.id, err)
+		}
+		return e.printDone(o.id, "unpaused")
+	}
+}
+
+func setupBulkPause(fs *flag.FlagSet, o *options) func(ctx context.Context, e *env) error {
+	o.selectorFlags(fs)
+	o.pauseFlags(fs)
+	o.snapshotFlags(fs)
+	return func(ctx context.Context, e *env) error {
+		pauser := &bulk.Pauser{Schedules: e.schedules, By: o.by, DryRun: o.dryRun}
+		ids, err := pauser.Select(ctx, bulk.Selector{Prefix: o.prefix, Memo: o.memoValues, SearchAttributes: o.searchAttributeValues})
+		if err != nil {
+			return err
+		}
+		if !o.dryRun {
+			// The snapshot is created before anything is paused, and saved again after each Schedule is paused.
+			file, err := bulk.CreateSnapshotFile(o.snapshot)
+			if errors.Is(err, os.ErrExist) {
+				return fmt.Errorf("%w: unpause with it or give another -snapshot", err)
+			}
+			if err != nil {
+				return err
+			}
+			pauser.Checkpoint = file.Save
+		}
+		snap, err := pauser.Pause(ctx, ids, o.note)
+		if err != nil && !o.dryRun {
+			return fmt.Errorf("%w (the snapshot %s records the schedules paused before it)", err, o.snapshot)
+		}
+		if err != nil {
+			return err
+		}
+		return e.printBulkPause(snap, o.snapshot, o.dryRun)
+	}
+}
+
+func setupBulkUnpause(fs *flag.FlagSet, o *options) func(ctx context.Context, e *env) error {
+	o.snapshotFlags(fs)
+	return func(ctx context.Context, e *env) error {
+		snap, err := bulk.LoadSnapshot(o.snapshot)
+		if err != nil {
+			return err
+		}
+		pauser := &bulk.Pauser{Schedules: e.schedules, DryRun: o.dryRun}
+		result, err := pauser.Unpause(ctx, snap)
+		if err != nil {
+			return err
+		}
+		return e.printBulkUnpause(result, o.dryRun)
+	}
+}
+
+func setupTrigger(fs *flag.FlagSet, o *options) func(ctx context.Context, e *env) error {
+	o.idFlag(fs)
+	o.overlapFlag(fs)
+	return func(ctx context.Context, e *env) error {
+		err := e.schedules.GetHandle(ctx, o.id).Trigger(ctx, client.ScheduleTriggerOptions{Overlap: o.overlapPolicy})
+		if err != nil {
+			return fmt.Errorf("unable to trigger schedule %s: %w", o.id, err)
+		}
+		return e.printDone(o.id, "triggered")
+	}
+}
+
+func setupBackfill(fs *flag.FlagSet, o *options) func(ctx context.Context, e *env) error {
+	o.idFlag(fs)
+	o.rangeFlags(fs, true)
+	o.overlapFlag(fs)
+	return func(ctx context.Context, e *env) error {
+		err := e.schedules.GetHandle(ctx, o.id).Backfill(ctx, client.ScheduleBackfillOptions{
+			Backfill: []client.ScheduleBackfill{{
+				Start:   o.startTime,
+				End:     o.endTime,
+				Overlap: o.overlapPolicy,
+			}},
+		})
+		if err != nil {
+			return fmt.Errorf("unable to backfill schedule %s: %w", o.id, err)
+		}
+		return e.printDone(o.id, "backfilled")
+	}
+}
+
+func setupDelete(fs *flag.FlagSet, o *options) func(ctx context.Context, e *env) error {
+	o.idFlag(fs)
+	return func(ctx context.Context, e *env) error {
+		if err := e.schedules.GetHandle(ctx, o.id).Delete(ctx); err != nil {
+			return fmt.Errorf("unable to delete schedule %s: %w", o.id, err)
+		}
+		return e.printDone(o.id, "deleted")
+	}
+}
+
+func setupPlan(fs *flag.FlagSet, o *options) func(ctx context.Context, e *env) error {
+	o.fileFlags(fs)
+	return func(ctx context.Context, e *env) error {
+		_, plan, err := makePlan(ctx, e, o)
+		if err != nil {
+			return err
+		}
+		return e.printPlan(plan)
+	}
+}
+
+func setupApply(fs *flag.FlagSet, o *options) func(ctx context.Context, e *env) error {
+	o.fileFlags(fs)
+	return func(ctx context.Context, e *env) error {
+		r, plan, err := makePlan(ctx, e, o)
+		if err != nil {
+			return err
+		}
+		if err := e.printPlan(plan); err != nil {
+			return err
+		}
+		return r.Apply(ctx, plan)
+	}
+}
+
+func makePlan(ctx context.Context, e *env, o *options) (*reconcile.Reconciler, *reconcile.Plan, error) {
+	defs, err := reconcile.Load(o.files...)
+	if err != nil {
+		return nil, nil, err
+	}
+	r := &reconcile.Reconciler{Schedules: e.schedules, DataConverter: e.dc, Prune: o.prune}
+	plan, err := r.Plan(ctx, defs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, plan, nil
+}
+
+// maxPreview is the largest number of action times preview shows for a time range.
+const maxPreview = 10000
+
+func setupPreview(fs *flag.FlagSet, o *options) func(ctx context.Context, e *env) error {
+	o.specFlags(fs)
+	o.previewFlags(fs)
+	o.rangeFlags(fs, false)
+	return func(ctx context.Context, e *env) error {
+		evaluator, err := schedule.NewEvaluator(o.spec)
+		if err != nil {
+			return err
+		}
+		if o.start == "" {
+			after := o.afterTime
+			if after.IsZero() {
+				after = time.Now()
+			}
+			return e.printActionTimes(evaluator.Location(), evaluator.NextN(after, o.count))
+		}
+		var times []schedule.ActionTime
+		after := o.startTime.Add(-time.Second)
+		for {
+			t, ok := evaluator.Next(after)
+			if !ok || !t.Nominal.Before(o.endTime) {
+				break
+			}
+			if len(times) == maxPreview {
+				return fmt.Errorf("more than %d action times between -start and -end; narrow the range", maxPreview)
+			}
+			times = append(times, t)
+			after = t.Nominal
+		}
+		return e.printActionTimes(evaluator.Location(), times)
+	}
+}
+
+func setupFillGaps(fs *flag.FlagSet, o *options) func(ctx context.Context, e *env) error {
+	o.idFlag(fs)
+	o.rangeFlags(fs, true)
+	o.overlapFlag(fs)
+	o.gapFlags(fs)
+	return func(ctx context.Context, e *env) error {
+		planner := &gaps.Planner{
+			Schedules:       e.schedules,
+			Workflows:       e.workflows,
+			Namespaces:      e.namespaces,
+			Namespace:       e.namespace,
+			BeyondRetention: o.beyondRetention,
+		}
+		plan, err := planner.Plan(ctx, o.id, o.startTime, o.endTime)
+		if errors.Is(err, gaps.ErrBeyondRetention) {
+			return fmt.Errorf("%w; start later, or pass -beyond-retention if the workflows of the schedule are still listed", err)
+		}
+		if err != nil {
+			return err
+		}
+		if err := setWindowOverlaps(plan.Windows, o.overlapPolicy, o.windowPolicies); err != nil {
+			return err
+		}
+		if !o.dryRun {
+			if err := planner.Apply(ctx, plan); err != nil {
+				return err
+			}
+		}
+		return e.printGaps(plan, !o.dryRun)
+	}
+}
+
+// setWindowOverlaps sets the overlap policy of each window: the one that policies gives for the first action time of
+// the window, or else overlap. It refuses policies for windows that don't exist, such as windows that changed since a dry run.
+func setWindowOverlaps(windows []gaps.Window, overlap enums.ScheduleOverlapPolicy, policies map[int64]enums.ScheduleOverlapPolicy) error {
+	starts := map[int64]bool{}
+	var startList []string
+	for i, window := range windows {
+		start := window.Times[0]
+		starts[start.UnixNano()] = true
+		startList = append(startList, start.Format(time.RFC3339))
+		windows[i].Overlap = overlap
+		if policy, ok := policies[start.UnixNano()]; ok {
+			windows[i].Overlap = policy
+		}
+	}
+	for start := range policies {
+		if !starts[start] {
+			return fmt.Errorf("-window-overlap: no window starts at %s; the windows start at: %s",
+				time.Unix(0, start).UTC().Format(time.RFC3339), strings.Join(startList, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	_ "time/tzdata"
+
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+
+	"documentation-samples-go/codec"
+	"github.com/temporalio/documentation-samples-go/schedule/gaps"
+)
+
+const (
+	exitOK     = 0
+	exitFailed = 1
+	exitUsage  = 2
+)
+
+// env holds what the commands share: the Schedule Client, the Workflow Client, the Data Converter of their Payloads and where to write results.
+type env struct {
+	schedules  client.ScheduleClient
+	workflows  gaps.Visibility
+	namespaces gaps.Namespaces
+	namespace  string
+	dc         converter.DataConverter
+	out        io.Writer
+	json       bool
+}
+
+// command is a subcommand. setup registers its flags and returns the function that runs it.
+// The returned function is only called once the flags are valid and, unless the command is offline, the client is connected.
+type command struct {
+	name    string
+	summary string
+	setup   func(fs *flag.FlagSet, o *options) func(ctx context.Context, e *env) error
+	offline bool
+}
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	global := flag.NewFlagSet("schedule", flag.ContinueOnError)
+	global.SetOutput(stderr)
+	address := global.String("address", client.DefaultHostPort, "Temporal Service address")
+	namespace := global.String("namespace", client.DefaultNamespace, "Namespace of the Schedules")
+	output := global.String("output", "text", "Output format: text or json")
+	global.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: schedule [flags] <command> [command flags]")
+		global.PrintDefaults()
+		fmt.Fprintln(stderr, "\nCommands:")
+		for _, cmd := range sortedCommands() {
+			fmt.Fprintf(stderr, "  %-9s %s\n", cmd.name, cmd.summary)
+		}
+	}
+	if err := global.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *output != "text" && *output != "json" {
+		fmt.Fprintf(stderr, "invalid -output %q: must be text or json\n", *output)
+		return exitUsage
+	}
+	if global.NArg() == 0 {
+		global.Usage()
+		return exitUsage
+	}
+	cmd, ok := commands[global.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n", global.Arg(0))
+		global.Usage()
+		return exitUsage
+	}
+
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: schedule [flags] %s [flags]\n%s\n", cmd.name, cmd.summary)
+		fs.PrintDefaults()
+	}
+	o := newOptions()
+	runCmd := cmd.setup(fs, o)
+	if err := fs.Parse(global.Args()[1:]); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "unexpected arguments: %v\n", fs.Args())
+		return exitUsage
+	}
+	fs.Visit(func(f *flag.Flag) { o.set[f.Name] = true })
+	if err := o.check(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	if cmd.offline {
+		if err := runCmd(ctx, &env{out: stdout, json: *output == "json"}); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitFailed
+		}
+		return exitOK
+	}
+
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		fmt.Fprintln(stderr, "Unable to create Data Converter:", err)
+		return exitFailed
+	}
+	c, err := client.Dial(client.Options{
+		HostPort:      *address,
+		Namespace:     *namespace,
+		DataConverter: dataConverter,
+	})
+	if err != nil {
+		fmt.Fprintln(stderr, "Unable to create Temporal Client:", err)
+		return exitFailed
+	}
+	defer c.Close()
+
+	e := &env{
+		schedules:  c.ScheduleClient(),
+		workflows:  c,
+		namespaces: c.WorkflowService(),
+		namespace:  *namespace,
+		dc:         dataConverter,
+		out:        stdout,
+		json:       *output == "json",
+	}
+	if err := runCmd(ctx, e); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailed
+	}
+	return exitOK
+}
+
+func sortedCommands() []command {
+	var cmds []command
+	for _, cmd := range commands {
+		cmds = append(cmds, cmd)
+	}
+	sort.Slice(cmds, func(i, j int) bool { return cmds[i].name < cmds[j].name })
+	return cmds
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+
+	"github.com/temporalio/documentation-samples-go/schedule"
+	"github.com/temporalio/documentation-samples-go/schedule/gaps"
+)
+
+func Test_RunUsage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "no command", args: nil},
+		{name: "unknown command", args: []string{"rename"}},
+		{name: "invalid output", args: []string{"-output", "yaml", "list"}},
+		{name: "missing id", args: []string{"describe"}},
+		{name: "unknown flag", args: []string{"delete", "-id", "a", "-force"}},
+		{name: "extra argument", args: []string{"delete", "-id", "a", "b"}},
+		{name: "invalid interval", args: []string{"create", "-id", "a", "-interval", "often"}},
+		{name: "invalid calendar", args: []string{"create", "-id", "a", "-calendar", "hour=25"}},
+		{name: "invalid args", args: []string{"create", "-id", "a", "-args", `{"a": 1}`}},
+		{name: "invalid overlap", args: []string{"trigger", "-id", "a", "-overlap", "sometimes"}},
+		{name: "update without changes", args: []string{"update", "-id", "a"}},
+		{name: "update with invalid memo", args: []string{"update", "-id", "a", "-memo", "team"}},
+		{name: "update with negative remaining actions", args: []string{"update", "-id", "a", "-remaining-actions", "-1"}},
+		{name: "bulk-pause without selector", args: []string{"bulk-pause", "-note", "deploy", "-snapshot", "paused.json"}},
+		{name: "bulk-pause without note", args: []string{"bulk-pause", "-prefix", "billing-", "-snapshot", "paused.json"}},
+		{name: "bulk-pause with invalid search attribute", args: []string{"bulk-pause", "-search-attribute", "Service", "-note", "deploy", "-snapshot", "paused.json"}},
+		{name: "bulk-unpause without snapshot", args: []string{"bulk-unpause", "-dry-run"}},
+		{name: "plan without file", args: []string{"plan", "-prune"}},
+		{name: "preview with half a range", args: []string{"preview", "-cron", "@daily", "-start", "2023-01-01T00:00:00Z"}},
+		{name: "preview with invalid skip", args: []string{"preview", "-cron", "@daily", "-skip", "hour=99"}},
+		{name: "fill-gaps with invalid window overlap", args: []string{"fill-gaps", "-id", "a", "-start", "2023-01-01T00:00:00Z", "-end", "2023-01-02T00:00:00Z", "-window-overlap", "2=skip"}},
+		{name: "fill-gaps without range", args: []string{"fill-gaps", "-id", "a", "-dry-run"}},
+		{name: "backfill without range", args: []string{"backfill", "-id", "a"}},
+		{name: "backfill reversed range", args: []string{"backfill", "-id", "a", "-start", "2023-01-02T00:00:00Z", "-end", "2023-01-01T00:00:00Z"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(context.Background(), test.args, &stdout, &stderr)
+			require.Equal(t, exitUsage, code)
+			require.Empty(t, stdout.String())
+			require.NotEmpty(t, stderr.String())
+		})
+	}
+}
+
+// parse registers the flags of the named command and parses args, as run does before connecting.
+func parse(t *testing.T, name string, args ...string) *options {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	o := newOptions()
+	commands[name].setup(fs, o)
+	require.NoError(t, fs.Parse(args))
+	fs.Visit(func(f *flag.Flag) { o.set[f.Name] = true })
+	require.NoError(t, o.check())
+	return o
+}
+
+func Test_Check(t *testing.T) {
+	o := parse(t, "create", "-id", "a",
+		"-cron", "0 9 * * *", "-interval", "1h/15m", "-interval", "30m", "-calendar", "hour=12",
+		"-args", `["x", 1]`, "-overlap", "buffer_one")
+	require.Equal(t, []string{"0 9 * * *"}, o.spec.CronExpressions)
+	require.Equal(t, []client.ScheduleIntervalSpec{{Every: time.Hour, Offset: 15 * time.Minute}, {Every: 30 * time.Minute}}, o.spec.Intervals)
+	require.Equal(t, []client.ScheduleCalendarSpec{{Hour: []client.ScheduleRange{{Start: 12}}}}, o.spec.Calendars)
+	require.Equal(t, []interface{}{"x", 1.0}, o.workflowArgs)
+	require.Equal(t, enums.SCHEDULE_OVERLAP_POLICY_BUFFER_ONE, o.overlapPolicy)
+
+	o = parse(t, "backfill", "-id", "a", "-start", "2023-01-01T00:00:00Z", "-end", "2023-01-02T00:00:00Z")
+	require.Equal(t, 24*time.Hour, o.endTime.Sub(o.startTime))
+}
+
+func Test_PatchSchedule(t *testing.T) {
+	current := func() client.Schedule {
+		return client.Schedule{
+			Spec: &client.ScheduleSpec{
+				CronExpressions: []string{"0 9 * * *"},
+				Intervals:       []client.ScheduleIntervalSpec{{Every: time.Hour}},
+				TimeZoneName:    "Europe/Paris",
+			},
+			Action: &client.ScheduleWorkflowAction{
+				ID:        "wf",
+				Workflow:  "ScheduleWorkflow",
+				Args:      []interface{}{"old"},
+				TaskQueue: "schedule",
+				Memo:      map[string]interface{}{"team": "billing"},
+			},
+		}
+	}
+	nine := schedule.CanonicalCalendar(client.ScheduleCalendarSpec{Hour: []client.ScheduleRange{{Start: 9}}})
+	eight := schedule.CanonicalCalendar(client.ScheduleCalendarSpec{Hour: []client.ScheduleRange{{Start: 8}}})
+
+	// Only the given fields change, and the current Schedule is left as it is.
+	old := current()
+	s, err := patchSchedule(old, parse(t, "update", "-id", "a", "-task-queue", "other", "-memo", "owner=ops", "-remove-memo", "team"))
+	require.NoError(t, err)
+	require.Equal(t, current(), old)
+	require.Equal(t, old.Spec, s.Spec)
+	require.Equal(t, &client.ScheduleWorkflowAction{
+		ID:        "wf",
+		Workflow:  "ScheduleWorkflow",
+		Args:      []interface{}{"old"},
+		TaskQueue: "other",
+		Memo:      map[string]interface{}{"owner": "ops"},
+	}, s.Action)
+
+	// -calendar replaces the calendars and keeps the intervals and the rest of the spec.
+	s, err = patchSchedule(current(), parse(t, "update", "-id", "a", "-calendar", "hour=8", "-args", `[]`))
+	require.NoError(t, err)
+	require.Equal(t, &client.ScheduleSpec{
+		Calendars:    []client.ScheduleCalendarSpec{eight},
+		Intervals:    []client.ScheduleIntervalSpec{{Every: time.Hour}},
+		TimeZoneName: "Europe/Paris",
+	}, s.Spec)
+	require.Empty(t, s.Action.(*client.ScheduleWorkflowAction).Args)
+
+	// The add and remove flags change single entries of the spec.
+	s, err = patchSchedule(current(), parse(t, "update", "-id", "a",
+		"-add-cron", "0 8 * * *", "-remove-interval", "1h", "-add-interval", "2h/5m"))
+	require.NoError(t, err)
+	require.Equal(t, []client.ScheduleCalendarSpec{nine, eight}, s.Spec.Calendars)
+	require.Equal(t, []client.ScheduleIntervalSpec{{Every: 2 * time.Hour, Offset: 5 * time.Minute}}, s.Spec.Intervals)
+
+	s, err = patchSchedule(current(), parse(t, "update", "-id", "a", "-remove-cron", "0 9 * * *"))
+	require.NoError(t, err)
+	require.Empty(t, s.Spec.Calendars)
+
+	_, err = patchSchedule(current(), parse(t, "update", "-id", "a", "-remove-cron", "0 10 * * *"))
+	require.EqualError(t, err, "the spec has no calendar second=0;minute=0;hour=10;day_of_month=1-31;month=1-12;day_of_week=0-6")
+	_, err = patchSchedule(current(), parse(t, "update", "-id", "a", "-remove-memo", "owner"))
+	require.EqualError(t, err, "the workflow memo has no key owner")
+
+	// Policies and state are allocated when missing.
+	s, err = patchSchedule(current(), parse(t, "update", "-id", "a",
+		"-overlap", "allow_all", "-catchup-window", "10m", "-pause-on-failure", "-remaining-actions", "3"))
+	require.NoError(t, err)
+	require.Equal(t, enums.SCHEDULE_OVERLAP_POLICY_ALLOW_ALL, s.Policy.Overlap)
+	require.Equal(t, 10*time.Minute, s.Policy.CatchupWindow)
+	require.True(t, s.Policy.PauseOnFailure)
+	require.True(t, s.State.LimitedActions)
+	require.Equal(t, 3, s.State.RemainingActions)
+}
+
+func Test_DiffSchedules(t *testing.T) {
+	dc := converter.GetDefaultDataConverter()
+	arg, err := dc.ToPayload("old")
+	require.NoError(t, err)
+	memo, err := dc.ToPayload("billing")
+	require.NoError(t, err)
+	old := client.Schedule{
+		Spec: &client.ScheduleSpec{Intervals: []client.ScheduleIntervalSpec{{Every: time.Hour}}},
+		Action: &client.ScheduleWorkflowAction{
+			ID:        "wf",
+			Workflow:  "ScheduleWorkflow",
+			Args:      []interface{}{arg},
+			TaskQueue: "schedule",
+			Memo:      map[string]interface{}{"team": memo},
+		},
+	}
+
+	// An unchanged Schedule has no changes, even though its arguments are payloads and its spec is not canonical.
+	s, err := patchSchedule(old, parse(t, "update", "-id", "a", "-args", `["old"]`, "-memo", "team=billing", "-remove-interval", "1h", "-add-interval", "1h"))
+	require.NoError(t, err)
+	changes, err := diffSchedules(&old, s, dc)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	s, err = patchSchedule(old, parse(t, "update", "-id", "a",
+		"-add-interval", "30m", "-args", `["new", 2]`, "-memo", `tier={"level":1}`, "-paused", "-note", "maintenance"))
+	require.NoError(t, err)
+	changes, err = diffSchedules(&old, s, dc)
+	require.NoError(t, err)
+	require.Equal(t, []fieldChange{
+		{Field: "spec.intervals", Added: []string{"30m"}},
+		{Field: "action.args", Removed: []string{`["old"]`}, Added: []string{`["new",2]`}},
+		{Field: "action.memo", Added: []string{`tier={"level":1}`}},
+		{Field: "state.note", Added: []string{"maintenance"}},
+		{Field: "state.paused", Removed: []string{"false"}, Added: []string{"true"}},
+	}, changes)
+
+	var stdout bytes.Buffer
+	require.NoError(t, (&env{out: &stdout}).printChanges(changes[:2]))
+	require.Equal(t, "spec.intervals\n  + 30m\naction.args\n  - [\"old\"]\n  + [\"new\",2]\n", stdout.String())
+}
+
+func Test_Preview(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), []string{
+		"preview", "-calendar", "hour=9;minute=30;day_of_week=1-5", "-time-zone", "America/New_York",
+		"-after", "2023-03-10T15:00:00Z", "-count", "2",
+	}, &stdout, &stderr)
+	require.Equal(t, exitOK, code, stderr.String())
+	require.Equal(t, "2023-03-13T09:30:00-04:00\n2023-03-14T09:30:00-04:00\n", stdout.String())
+
+	stdout.Reset()
+	code = run(context.Background(), []string{
+		"-output", "json", "preview", "-interval", "1h", "-jitter", "5m",
+		"-start", "2023-05-01T00:00:00Z", "-end", "2023-05-01T02:00:00Z",
+	}, &stdout, &stderr)
+	require.Equal(t, exitOK, code, stderr.String())
+	require.JSONEq(t, `[
+		{"nominal": "2023-05-01T00:00:00Z", "latest": "2023-05-01T00:05:00Z"},
+		{"nominal": "2023-05-01T01:00:00Z", "latest": "2023-05-01T01:05:00Z"}
+	]`, stdout.String())
+}
+
+func Test_SetWindowOverlaps(t *testing.T) {
+	windows := []gaps.Window{
+		{Times: []time.Time{time.Date(2023, 1, 1, 1, 0, 0, 0, time.UTC), time.Date(2023, 1, 1, 2, 0, 0, 0, time.UTC)}},
+		{Times: []time.Time{time.Date(2023, 1, 1, 5, 0, 0, 0, time.UTC)}},
+	}
+	o := parse(t, "fill-gaps", "-id", "a", "-start", "2023-01-01T00:00:00Z", "-end", "2023-01-02T00:00:00Z",
+		"-overlap", "buffer_all", "-window-overlap", "2023-01-01T06:00:00+01:00=allow_all")
+	require.NoError(t, setWindowOverlaps(windows, o.overlapPolicy, o.windowPolicies))
+	require.Equal(t, enums.SCHEDULE_OVERLAP_POLICY_BUFFER_ALL, windows[0].Overlap)
+	require.Equal(t, enums.SCHEDULE_OVERLAP_POLICY_ALLOW_ALL, windows[1].Overlap)
+
+	// A window that changed since the dry run no longer starts at the time given.
+	o = parse(t, "fill-gaps", "-id", "a", "-start", "2023-01-01T00:00:00Z", "-end", "2023-01-02T00:00:00Z",
+		"-window-overlap", "2023-01-01T02:00:00Z=allow_all")
+	err := setWindowOverlaps(windows, o.overlapPolicy, o.windowPolicies)
+	require.ErrorContains(t, err, "no window starts at 2023-01-01T02:00:00Z; the windows start at: 2023-01-01T01:00:00Z, 2023-01-01T05:00:00Z")
+}
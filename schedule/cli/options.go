@@ -0,0 +1,374 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+	"time"
+
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/client"
+
+	"github.com/temporalio/documentation-samples-go/schedule"
+)
+
+const (
+	defaultWorkflowType = "ScheduleWorkflow"
+	defaultTaskQueue    = "schedule"
+)
+
+// stringList is a flag that can be repeated.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *stringList) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+// options holds the flags of a command. Each command registers only the flags it uses.
+type options struct {
+	id            string
+	cron          stringList
+	interval      stringList
+	calendar      stringList
+	workflowType  string
+	workflowID    string
+	taskQueue     string
+	args          string
+	overlap       string
+	note          string
+	paused        bool
+	start         string
+	end           string
+	pageSize      int
+	files         stringList
+	prune         bool
+	skip          stringList
+	timeZone      string
+	jitter        time.Duration
+	startAt       string
+	endAt         string
+	after         string
+	count         int
+	windowOverlap stringList
+	dryRun        bool
+	// beyondRetention is the -beyond-retention flag of fill-gaps.
+	beyondRetention bool
+	// Patch flags of update.
+	addCron          stringList
+	removeCron       stringList
+	addInterval      stringList
+	removeInterval   stringList
+	addCalendar      stringList
+	removeCalendar   stringList
+	memo             stringList
+	removeMemo       stringList
+	catchupWindow    time.Duration
+	pauseOnFailure   bool
+	remainingActions int
+	// Flags of bulk-pause and bulk-unpause.
+	prefix          string
+	searchAttribute stringList
+	by              string
+	snapshot        string
+
+	needID    bool
+	needRange bool
+	// needChange requires a flag other than -id.
+	needChange bool
+	needFiles  bool
+	// needSelector requires -prefix, -memo or -search-attribute, so that a bulk command does not match every Schedule by mistake.
+	needSelector bool
+	needNote     bool
+	needSnapshot bool
+	// set holds the names of the flags given on the command line.
+	set map[string]bool
+
+	// Parsed by check.
+	spec          client.ScheduleSpec
+	workflowArgs  []interface{}
+	overlapPolicy enums.ScheduleOverlapPolicy
+	startTime     time.Time
+	endTime       time.Time
+	afterTime     time.Time
+	// windowPolicies maps the first action time of windows, in Unix nanoseconds, to their overlap policy.
+	// Windows are keyed by time rather than by number, so that a policy can't go to another window when the gaps change.
+	windowPolicies map[int64]enums.ScheduleOverlapPolicy
+	// addSpec and removeSpec hold the calendars and intervals that update adds to the spec and removes from it.
+	addSpec               client.ScheduleSpec
+	removeSpec            client.ScheduleSpec
+	memoValues            map[string]interface{}
+	searchAttributeValues map[string]interface{}
+}
+
+func newOptions() *options {
+	return &options{set: map[string]bool{}}
+}
+
+func (o *options) idFlag(fs *flag.FlagSet) {
+	fs.StringVar(&o.id, "id", "", "Schedule Id (required)")
+	o.needID = true
+}
+
+func (o *options) specFlags(fs *flag.FlagSet) {
+	fs.Var(&o.cron, "cron", `Cron expression, such as "0 9 * * MON-FRI" (repeatable)`)
+	fs.Var(&o.interval, "interval", `Interval, optionally with an offset, such as "1h" or "1h/15m" (repeatable)`)
+	fs.Var(&o.calendar, "calendar", `Calendar, such as "hour=9;minute=30;day_of_week=1-5" (repeatable)`)
+}
+
+func (o *options) actionFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.workflowType, "workflow-type", defaultWorkflowType, "Workflow type to start")
+	fs.StringVar(&o.workflowID, "workflow-id", "", "Workflow Id to start (default: the Schedule Id with a _workflow suffix)")
+	fs.StringVar(&o.taskQueue, "task-queue", defaultTaskQueue, "Task Queue of the Workflow")
+	fs.StringVar(&o.args, "args", "", `Workflow arguments as a JSON array, such as '["a", 1]'`)
+}
+
+func (o *options) overlapFlag(fs *flag.FlagSet) {
+	fs.StringVar(&o.overlap, "overlap", "", "Overlap policy: skip, buffer_one, buffer_all, cancel_other, terminate_other or allow_all")
+}
+
+func (o *options) noteFlag(fs *flag.FlagSet, usage string) {
+	fs.StringVar(&o.note, "note", "", usage)
+}
+
+func (o *options) pausedFlag(fs *flag.FlagSet) {
+	fs.BoolVar(&o.paused, "paused", false, "Whether the Schedule is paused")
+}
+
+// rangeFlags registers -start and -end. An optional range may be left out, but not given only in part.
+func (o *options) rangeFlags(fs *flag.FlagSet, required bool) {
+	usage := " (required)"
+	if !required {
+		usage = ""
+	}
+	fs.StringVar(&o.start, "start", "", "Start of the time range, in RFC 3339 format"+usage)
+	fs.StringVar(&o.end, "end", "", "End of the time range, in RFC 3339 format"+usage)
+	o.needRange = required
+}
+
+// previewFlags registers the flags of a spec that the Temporal Service would otherwise default, and those that pick the times to show.
+func (o *options) previewFlags(fs *flag.FlagSet) {
+	fs.Var(&o.skip, "skip", `Calendar of times to skip, such as "month=12;day_of_month=25" (repeatable)`)
+	fs.StringVar(&o.timeZone, "time-zone", "", "Time zone of the calendars and cron expressions, such as America/New_York (default UTC)")
+	fs.DurationVar(&o.jitter, "jitter", 0, "Maximum random delay of each action")
+	fs.StringVar(&o.startAt, "start-at", "", "Time the spec starts, in RFC 3339 format")
+	fs.StringVar(&o.endAt, "end-at", "", "Time the spec ends, in RFC 3339 format")
+	fs.StringVar(&o.after, "after", "", "Show the action times after this time, in RFC 3339 format (default now)")
+	fs.IntVar(&o.count, "count", 10, "Number of action times to show after -after; ignored with -start and -end")
+}
+
+// patchFlags registers the flags of update that change part of a field, or a field that create does not set.
+func (o *options) patchFlags(fs *flag.FlagSet) {
+	fs.Var(&o.addCron, "add-cron", "Cron expression to add to the spec (repeatable)")
+	fs.Var(&o.removeCron, "remove-cron", "Cron expression to remove from the spec (repeatable)")
+	fs.Var(&o.addInterval, "add-interval", "Interval to add to the spec (repeatable)")
+	fs.Var(&o.removeInterval, "remove-interval", "Interval to remove from the spec (repeatable)")
+	fs.Var(&o.addCalendar, "add-calendar", "Calendar to add to the spec (repeatable)")
+	fs.Var(&o.removeCalendar, "remove-calendar", "Calendar to remove from the spec (repeatable)")
+	fs.Var(&o.memo, "memo", `Memo of the Workflow to set, as key=value, where value is JSON or else a string (repeatable)`)
+	fs.Var(&o.removeMemo, "remove-memo", "Memo key of the Workflow to remove (repeatable)")
+	fs.DurationVar(&o.catchupWindow, "catchup-window", 0, "How long after a missed action time the action is still taken")
+	fs.BoolVar(&o.pauseOnFailure, "pause-on-failure", false, "Whether a failed Workflow pauses the Schedule")
+	fs.IntVar(&o.remainingActions, "remaining-actions", 0, "Number of actions left before the Schedule stops taking actions, or 0 for no limit")
+	fs.BoolVar(&o.dryRun, "dry-run", false, "Print the changes without updating the Schedule")
+}
+
+// selectorFlags registers the flags that pick the Schedules of a bulk command.
+func (o *options) selectorFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.prefix, "prefix", "", "Id prefix of the Schedules to match")
+	fs.Var(&o.memo, "memo", "Memo of the Schedules to match, as key=value, where value is JSON or else a string (repeatable)")
+	fs.Var(&o.searchAttribute, "search-attribute", "Search Attribute of the Schedules to match, as key=value, where value is JSON or else a string (repeatable)")
+	o.needSelector = true
+}
+
+// pauseFlags registers the flags of bulk-pause that record who paused the Schedules and why.
+func (o *options) pauseFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.note, "note", "", "Reason for pausing (required)")
+	fs.StringVar(&o.by, "by", currentUser(), "Who pauses the Schedules, for their notes")
+	o.needNote = true
+}
+
+// snapshotFlags registers the flags of bulk-pause and bulk-unpause that pick the snapshot file.
+func (o *options) snapshotFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.snapshot, "snapshot", "", "Snapshot file of the Schedules that bulk-pause paused (required)")
+	fs.BoolVar(&o.dryRun, "dry-run", false, "Print what would change without changing any Schedule")
+	o.needSnapshot = true
+}
+
+func (o *options) fileFlags(fs *flag.FlagSet) {
+	fs.Var(&o.files, "file", "Schedule file, in YAML or JSON (required, repeatable)")
+	fs.BoolVar(&o.prune, "prune", false, "Delete the Schedules that were created from schedule files and are no longer in them")
+	o.needFiles = true
+}
+
+func (o *options) gapFlags(fs *flag.FlagSet) {
+	fs.Var(&o.windowOverlap, "window-overlap", `Overlap policy of the window that starts at a time, as -dry-run prints it, such as "2023-05-01T03:00:00Z=allow_all" (repeatable; default -overlap)`)
+	fs.BoolVar(&o.dryRun, "dry-run", false, "Print the missed action times and windows without backfilling them")
+	fs.BoolVar(&o.beyondRetention, "beyond-retention", false, "Allow a -start before the retention period of the Namespace, when the Workflows of the Schedule are still listed")
+}
+
+// check validates the flags and parses them into spec, workflowArgs, overlapPolicy, startTime, endTime and the patch fields.
+func (o *options) check() error {
+	if o.needID && o.id == "" {
+		return errors.New("-id is required")
+	}
+	if o.needChange && len(o.set) == 1 && o.set["id"] {
+		return errors.New("nothing to change: give at least one flag besides -id")
+	}
+	if o.needFiles && len(o.files) == 0 {
+		return errors.New("-file is required")
+	}
+	if o.needSelector && o.prefix == "" && len(o.memo) == 0 && len(o.searchAttribute) == 0 {
+		return errors.New("-prefix, -memo or -search-attribute is required")
+	}
+	if o.needNote && o.note == "" {
+		return errors.New("-note is required")
+	}
+	if o.needSnapshot && o.snapshot == "" {
+		return errors.New("-snapshot is required")
+	}
+	var err error
+	if o.spec, err = parseSpec(o.cron, o.interval, o.calendar); err != nil {
+		return err
+	}
+	if o.addSpec, err = parseSpec(o.addCron, o.addInterval, o.addCalendar); err != nil {
+		return err
+	}
+	if o.removeSpec, err = parseSpec(o.removeCron, o.removeInterval, o.removeCalendar); err != nil {
+		return err
+	}
+	if o.args != "" {
+		if err := json.Unmarshal([]byte(o.args), &o.workflowArgs); err != nil {
+			return fmt.Errorf("invalid -args: must be a JSON array: %w", err)
+		}
+	}
+	if o.memoValues, err = parsePairs("memo", o.memo); err != nil {
+		return err
+	}
+	if o.searchAttributeValues, err = parsePairs("search-attribute", o.searchAttribute); err != nil {
+		return err
+	}
+	if o.remainingActions < 0 {
+		return errors.New("-remaining-actions must not be negative")
+	}
+	if o.catchupWindow < 0 {
+		return errors.New("-catchup-window must not be negative")
+	}
+	o.overlapPolicy, err = schedule.ParseOverlapPolicy(o.overlap)
+	if err != nil {
+		return err
+	}
+	for _, s := range o.skip {
+		calendar, err := schedule.ParseCalendar(s)
+		if err != nil {
+			return err
+		}
+		o.spec.Skip = append(o.spec.Skip, calendar)
+	}
+	o.spec.TimeZoneName = o.timeZone
+	o.spec.Jitter = o.jitter
+	if o.jitter < 0 {
+		return errors.New("-jitter must not be negative")
+	}
+	times := []struct {
+		name  string
+		value string
+		t     *time.Time
+	}{
+		{"start-at", o.startAt, &o.spec.StartAt},
+		{"end-at", o.endAt, &o.spec.EndAt},
+		{"after", o.after, &o.afterTime},
+	}
+	for _, f := range times {
+		if f.value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, f.value)
+		if err != nil {
+			return fmt.Errorf("invalid -%s: %w", f.name, err)
+		}
+		*f.t = t
+	}
+	o.windowPolicies = map[int64]enums.ScheduleOverlapPolicy{}
+	for _, s := range o.windowOverlap {
+		start, name, ok := strings.Cut(s, "=")
+		t, err := time.Parse(time.RFC3339, start)
+		if !ok || err != nil {
+			return fmt.Errorf("invalid -window-overlap %q: must be start=policy, with the RFC 3339 start time of a window", s)
+		}
+		if o.windowPolicies[t.UnixNano()], err = schedule.ParseOverlapPolicy(name); err != nil {
+			return err
+		}
+	}
+	if o.needRange || o.start != "" || o.end != "" {
+		if o.start == "" || o.end == "" {
+			return errors.New("-start and -end are required")
+		}
+		if o.startTime, err = time.Parse(time.RFC3339, o.start); err != nil {
+			return fmt.Errorf("invalid -start: %w", err)
+		}
+		if o.endTime, err = time.Parse(time.RFC3339, o.end); err != nil {
+			return fmt.Errorf("invalid -end: %w", err)
+		}
+		if !o.endTime.After(o.startTime) {
+			return errors.New("-end must be after -start")
+		}
+	}
+	return nil
+}
+
+// parseSpec returns the spec of the given cron, interval and calendar flags.
+func parseSpec(cron, intervals, calendars stringList) (client.ScheduleSpec, error) {
+	spec := client.ScheduleSpec{CronExpressions: cron}
+	for _, s := range intervals {
+		interval, err := schedule.ParseInterval(s)
+		if err != nil {
+			return client.ScheduleSpec{}, err
+		}
+		spec.Intervals = append(spec.Intervals, interval)
+	}
+	for _, s := range calendars {
+		calendar, err := schedule.ParseCalendar(s)
+		if err != nil {
+			return client.ScheduleSpec{}, err
+		}
+		spec.Calendars = append(spec.Calendars, calendar)
+	}
+	return spec, nil
+}
+
+// parsePairs returns the key=value pairs of the flag name, with each value decoded from JSON, or kept as a string when it is not JSON.
+func parsePairs(name string, pairs stringList) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	for _, s := range pairs {
+		key, value, ok := strings.Cut(s, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid -%s %q: must be key=value", name, s)
+		}
+		var v interface{}
+		if json.Unmarshal([]byte(value), &v) != nil {
+			v = value
+		}
+		values[key] = v
+	}
+	return values, nil
+}
+
+// currentUser returns the name of the user running the command, or "unknown".
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "unknown"
+}
@@ -0,0 +1,355 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+
+	"github.com/temporalio/documentation-samples-go/schedule"
+	"github.com/temporalio/documentation-samples-go/schedule/bulk"
+	"github.com/temporalio/documentation-samples-go/schedule/gaps"
+	"github.com/temporalio/documentation-samples-go/schedule/reconcile"
+)
+
+// scheduleView is what describe and list show of a Schedule.
+type scheduleView struct {
+	ID              string        `json:"id"`
+	Paused          bool          `json:"paused"`
+	Note            string        `json:"note,omitempty"`
+	Cron            []string      `json:"cron,omitempty"`
+	Intervals       []string      `json:"intervals,omitempty"`
+	Calendars       []string      `json:"calendars,omitempty"`
+	WorkflowType    string        `json:"workflowType,omitempty"`
+	WorkflowID      string        `json:"workflowId,omitempty"`
+	TaskQueue       string        `json:"taskQueue,omitempty"`
+	Args            []interface{} `json:"args,omitempty"`
+	Overlap         string        `json:"overlap,omitempty"`
+	NextActionTimes []time.Time   `json:"nextActionTimes,omitempty"`
+	RecentActions   []time.Time   `json:"recentActions,omitempty"`
+	Running         []string      `json:"running,omitempty"`
+}
+
+// describeView returns the view of a described Schedule, with the Workflow arguments decoded by dc.
+func describeView(id string, desc *client.ScheduleDescription, dc converter.DataConverter) (scheduleView, error) {
+	view := scheduleView{ID: id}
+	s := desc.Schedule
+	if s.State != nil {
+		view.Paused = s.State.Paused
+		view.Note = s.State.Note
+	}
+	if s.Spec != nil {
+		setSpec(&view, s.Spec)
+	}
+	if s.Policy != nil {
+		view.Overlap = schedule.FormatOverlapPolicy(s.Policy.Overlap)
+	}
+	if action, ok := s.Action.(*client.ScheduleWorkflowAction); ok {
+		view.WorkflowType = fmt.Sprint(action.Workflow)
+		view.WorkflowID = action.ID
+		view.TaskQueue = action.TaskQueue
+		args, err := decodeArgs(action.Args, dc)
+		if err != nil {
+			return scheduleView{}, err
+		}
+		view.Args = args
+	}
+	view.NextActionTimes = desc.Info.NextActionTimes
+	for _, action := range desc.Info.RecentActions {
+		view.RecentActions = append(view.RecentActions, action.ScheduleTime)
+	}
+	for _, run := range desc.Info.RunningWorkflows {
+		view.Running = append(view.Running, run.WorkflowID)
+	}
+	return view, nil
+}
+
+// decodeArgs decodes with dc the arguments that Describe returns as payloads, and keeps the others as they are.
+func decodeArgs(args []interface{}, dc converter.DataConverter) ([]interface{}, error) {
+	var out []interface{}
+	for _, arg := range args {
+		if payload, ok := arg.(*commonpb.Payload); ok {
+			var value interface{}
+			if err := dc.FromPayload(payload, &value); err != nil {
+				return nil, fmt.Errorf("unable to decode workflow arguments: %w", err)
+			}
+			arg = value
+		}
+		out = append(out, arg)
+	}
+	return out, nil
+}
+
+// listView returns the view of a listed Schedule, which has no Action details or policies.
+func listView(entry *client.ScheduleListEntry) scheduleView {
+	view := scheduleView{
+		ID:              entry.ID,
+		Paused:          entry.Paused,
+		Note:            entry.Note,
+		WorkflowType:    entry.WorkflowType.Name,
+		NextActionTimes: entry.NextActionTimes,
+	}
+	if entry.Spec != nil {
+		setSpec(&view, entry.Spec)
+	}
+	for _, action := range entry.RecentActions {
+		view.RecentActions = append(view.RecentActions, action.ScheduleTime)
+	}
+	return view
+}
+
+func setSpec(view *scheduleView, spec *client.ScheduleSpec) {
+	view.Cron = spec.CronExpressions
+	for _, interval := range spec.Intervals {
+		view.Intervals = append(view.Intervals, schedule.FormatInterval(interval))
+	}
+	for _, calendar := range spec.Calendars {
+		view.Calendars = append(view.Calendars, schedule.FormatCalendar(calendar))
+	}
+}
+
+func (e *env) printJSON(v interface{}) error {
+	enc := json.NewEncoder(e.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printDone reports that a command changed a Schedule.
+func (e *env) printDone(id, result string) error {
+	if e.json {
+		return e.printJSON(map[string]string{"id": id, "result": result})
+	}
+	_, err := fmt.Fprintf(e.out, "Schedule %s %s\n", id, result)
+	return err
+}
+
+func (e *env) printSchedule(view scheduleView) error {
+	if e.json {
+		return e.printJSON(view)
+	}
+	w := tabwriter.NewWriter(e.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "ID:\t%s\n", view.ID)
+	fmt.Fprintf(w, "Paused:\t%t\n", view.Paused)
+	fmt.Fprintf(w, "Note:\t%s\n", view.Note)
+	fmt.Fprintf(w, "Cron:\t%s\n", strings.Join(view.Cron, ", "))
+	fmt.Fprintf(w, "Intervals:\t%s\n", strings.Join(view.Intervals, ", "))
+	fmt.Fprintf(w, "Calendars:\t%s\n", strings.Join(view.Calendars, ", "))
+	fmt.Fprintf(w, "Workflow type:\t%s\n", view.WorkflowType)
+	fmt.Fprintf(w, "Workflow ID:\t%s\n", view.WorkflowID)
+	fmt.Fprintf(w, "Task Queue:\t%s\n", view.TaskQueue)
+	args, err := json.Marshal(view.Args)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Args:\t%s\n", args)
+	fmt.Fprintf(w, "Overlap:\t%s\n", view.Overlap)
+	fmt.Fprintf(w, "Next actions:\t%s\n", formatTimes(view.NextActionTimes))
+	fmt.Fprintf(w, "Recent actions:\t%s\n", formatTimes(view.RecentActions))
+	fmt.Fprintf(w, "Running:\t%s\n", strings.Join(view.Running, ", "))
+	return w.Flush()
+}
+
+func (e *env) printList(views []scheduleView) error {
+	if e.json {
+		return e.printJSON(views)
+	}
+	w := tabwriter.NewWriter(e.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tPAUSED\tWORKFLOW TYPE\tNEXT ACTION\tNOTE")
+	for _, view := range views {
+		next := ""
+		if len(view.NextActionTimes) > 0 {
+			next = view.NextActionTimes[0].Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%t\t%s\t%s\t%s\n", view.ID, view.Paused, view.WorkflowType, next, view.Note)
+	}
+	return w.Flush()
+}
+
+func (e *env) printPlan(plan *reconcile.Plan) error {
+	if e.json {
+		return e.printJSON(plan)
+	}
+	return plan.Write(e.out)
+}
+
+// patchView is what update shows of the changes it makes.
+type patchView struct {
+	ID      string        `json:"id"`
+	Changes []fieldChange `json:"changes"`
+	Applied bool          `json:"applied"`
+}
+
+// printChanges prints the changes of an update as a diff, with the removed values of each field before the added ones.
+// The JSON output has the changes in the view that printPatch prints.
+func (e *env) printChanges(changes []fieldChange) error {
+	if e.json {
+		return nil
+	}
+	for _, change := range changes {
+		fmt.Fprintln(e.out, change.Field)
+		for _, value := range change.Removed {
+			fmt.Fprintf(e.out, "  - %s\n", value)
+		}
+		for _, value := range change.Added {
+			fmt.Fprintf(e.out, "  + %s\n", value)
+		}
+	}
+	return nil
+}
+
+func (e *env) printPatch(view patchView, dryRun bool) error {
+	if e.json {
+		return e.printJSON(view)
+	}
+	switch {
+	case len(view.Changes) == 0:
+		return e.printDone(view.ID, "unchanged")
+	case dryRun:
+		_, err := fmt.Fprintln(e.out, "Dry run: nothing was updated")
+		return err
+	default:
+		return e.printDone(view.ID, "updated")
+	}
+}
+
+// printActionTimes prints action times in the time zone of their spec, with the latest time jitter allows when there is jitter.
+func (e *env) printActionTimes(loc *time.Location, times []schedule.ActionTime) error {
+	if e.json {
+		if times == nil {
+			times = []schedule.ActionTime{}
+		}
+		return e.printJSON(times)
+	}
+	for _, t := range times {
+		line := t.Nominal.In(loc).Format(time.RFC3339)
+		if t.Latest.After(t.Nominal) {
+			line += " to " + t.Latest.In(loc).Format(time.RFC3339)
+		}
+		if _, err := fmt.Fprintln(e.out, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gapsView is what fill-gaps shows of a gaps.Plan.
+type gapsView struct {
+	ID       string       `json:"id"`
+	Expected int          `json:"expected"`
+	Missing  []time.Time  `json:"missing"`
+	Windows  []windowView `json:"windows"`
+	Applied  bool         `json:"applied"`
+}
+
+type windowView struct {
+	Start   time.Time   `json:"start"`
+	End     time.Time   `json:"end"`
+	Times   []time.Time `json:"times"`
+	Overlap string      `json:"overlap"`
+}
+
+func (e *env) printGaps(plan *gaps.Plan, applied bool) error {
+	view := gapsView{ID: plan.ScheduleID, Expected: len(plan.Expected), Missing: plan.Missing, Windows: []windowView{}, Applied: applied}
+	if view.Missing == nil {
+		view.Missing = []time.Time{}
+	}
+	for _, window := range plan.Windows {
+		backfill := window.Backfill()
+		overlap := schedule.FormatOverlapPolicy(window.Overlap)
+		if overlap == "" {
+			overlap = "schedule"
+		}
+		view.Windows = append(view.Windows, windowView{Start: backfill.Start, End: backfill.End, Times: window.Times, Overlap: overlap})
+	}
+	if e.json {
+		return e.printJSON(view)
+	}
+	fmt.Fprintf(e.out, "Schedule %s: %d action times expected, %d missing, in %d windows\n", view.ID, view.Expected, len(view.Missing), len(view.Windows))
+	for i, window := range view.Windows {
+		fmt.Fprintf(e.out, "Window %d: %s to %s, %d actions, overlap %s\n",
+			i+1, window.Times[0].Format(time.RFC3339), window.End.Format(time.RFC3339), len(window.Times), window.Overlap)
+	}
+	var err error
+	switch {
+	case len(view.Windows) == 0:
+	case applied:
+		_, err = fmt.Fprintf(e.out, "Backfilled %d windows\n", len(view.Windows))
+	default:
+		_, err = fmt.Fprintln(e.out, "Dry run: nothing was backfilled")
+	}
+	return err
+}
+
+// bulkPauseView is what bulk-pause shows of its snapshot.
+type bulkPauseView struct {
+	*bulk.Snapshot
+	File    string `json:"file,omitempty"`
+	Applied bool   `json:"applied"`
+}
+
+func (e *env) printBulkPause(snap *bulk.Snapshot, file string, dryRun bool) error {
+	if e.json {
+		view := bulkPauseView{Snapshot: snap, Applied: !dryRun}
+		if !dryRun {
+			view.File = file
+		}
+		return e.printJSON(view)
+	}
+	verb := "Paused"
+	if dryRun {
+		verb = "Would pause"
+	}
+	for _, id := range snap.Paused {
+		fmt.Fprintf(e.out, "%s %s\n", verb, id)
+	}
+	for _, s := range snap.AlreadyPaused {
+		fmt.Fprintf(e.out, "Already paused %s: %s\n", s.ID, s.Reason)
+	}
+	var err error
+	if dryRun {
+		_, err = fmt.Fprintln(e.out, "Dry run: nothing was paused")
+	} else {
+		_, err = fmt.Fprintf(e.out, "Saved the snapshot to %s\n", file)
+	}
+	return err
+}
+
+// bulkUnpauseView is what bulk-unpause shows of its result.
+type bulkUnpauseView struct {
+	*bulk.UnpauseResult
+	Applied bool `json:"applied"`
+}
+
+func (e *env) printBulkUnpause(result *bulk.UnpauseResult, dryRun bool) error {
+	if e.json {
+		return e.printJSON(bulkUnpauseView{UnpauseResult: result, Applied: !dryRun})
+	}
+	verb := "Unpaused"
+	if dryRun {
+		verb = "Would unpause"
+	}
+	for _, id := range result.Unpaused {
+		fmt.Fprintf(e.out, "%s %s\n", verb, id)
+	}
+	for _, s := range result.Skipped {
+		fmt.Fprintf(e.out, "Skipped %s: %s\n", s.ID, s.Reason)
+	}
+	if dryRun {
+		_, err := fmt.Fprintln(e.out, "Dry run: nothing was unpaused")
+		return err
+	}
+	return nil
+}
+
+func formatTimes(times []time.Time) string {
+	s := make([]string, len(times))
+	for i, t := range times {
+		s[i] = t.Format(time.RFC3339)
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,377 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/temporal"
+
+	"github.com/temporalio/documentation-samples-go/schedule"
+)
+
+func setupUpdate(fs *flag.FlagSet, o *options) func(ctx context.Context, e *env) error {
+	o.idFlag(fs)
+	o.specFlags(fs)
+	o.actionFlags(fs)
+	o.overlapFlag(fs)
+	o.noteFlag(fs, "Note about the Schedule")
+	o.pausedFlag(fs)
+	o.patchFlags(fs)
+	o.needChange = true
+	return func(ctx context.Context, e *env) error {
+		view := patchView{ID: o.id, Changes: []fieldChange{}}
+		err := e.schedules.GetHandle(ctx, o.id).Update(ctx, client.ScheduleUpdateOptions{
+			DoUpdate: func(input client.ScheduleUpdateInput) (*client.ScheduleUpdate, error) {
+				old := input.Description.Schedule
+				s, err := patchSchedule(old, o)
+				if err != nil {
+					return nil, err
+				}
+				if view.Changes, err = diffSchedules(&old, s, e.dc); err != nil {
+					return nil, err
+				}
+				// The changes are shown before they are applied, so that a failed update still shows what it tried.
+				if err := e.printChanges(view.Changes); err != nil {
+					return nil, err
+				}
+				if o.dryRun || len(view.Changes) == 0 {
+					return nil, temporal.ErrSkipScheduleUpdate
+				}
+				return &client.ScheduleUpdate{Schedule: s}, nil
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("unable to update schedule %s: %w", o.id, err)
+		}
+		view.Applied = !o.dryRun && len(view.Changes) > 0
+		return e.printPatch(view, o.dryRun)
+	}
+}
+
+// patchSchedule returns a copy of s with the fields of the given flags changed, and the others as they are.
+// -cron and -calendar replace the calendars of the spec and -interval replaces its intervals,
+// while the -add and -remove flags change single calendars, intervals and memo keys.
+// s is not changed.
+func patchSchedule(s client.Schedule, o *options) (*client.Schedule, error) {
+	out := s
+	var err error
+	if out.Spec, err = patchSpec(s.Spec, o); err != nil {
+		return nil, err
+	}
+	if out.Action, err = patchAction(s.Action, o); err != nil {
+		return nil, err
+	}
+	out.Policy = clone(s.Policy)
+	if o.set["overlap"] {
+		out.Policy.Overlap = o.overlapPolicy
+	}
+	if o.set["catchup-window"] {
+		out.Policy.CatchupWindow = o.catchupWindow
+	}
+	if o.set["pause-on-failure"] {
+		out.Policy.PauseOnFailure = o.pauseOnFailure
+	}
+	out.State = clone(s.State)
+	if o.set["note"] {
+		out.State.Note = o.note
+	}
+	if o.set["paused"] {
+		out.State.Paused = o.paused
+	}
+	if o.set["remaining-actions"] {
+		out.State.LimitedActions = o.remainingActions > 0
+		out.State.RemainingActions = o.remainingActions
+	}
+	return &out, nil
+}
+
+func patchSpec(s *client.ScheduleSpec, o *options) (*client.ScheduleSpec, error) {
+	if !o.anySet("cron", "interval", "calendar", "add-cron", "remove-cron", "add-interval", "remove-interval", "add-calendar", "remove-calendar") {
+		return s, nil
+	}
+	spec, err := schedule.CanonicalSpec(*clone(s))
+	if err != nil {
+		return nil, err
+	}
+	if o.set["cron"] || o.set["calendar"] {
+		spec.Calendars = nil
+	}
+	if o.set["interval"] {
+		spec.Intervals = nil
+	}
+
+	remove := o.removeSpec
+	remove.TimeZoneName = spec.TimeZoneName
+	if remove, err = schedule.CanonicalSpec(remove); err != nil {
+		return nil, err
+	}
+	for _, calendar := range remove.Calendars {
+		i := indexCalendar(spec.Calendars, calendar)
+		if i < 0 {
+			return nil, fmt.Errorf("the spec has no calendar %s", schedule.FormatCalendar(calendar))
+		}
+		spec.Calendars = append(spec.Calendars[:i:i], spec.Calendars[i+1:]...)
+	}
+	for _, interval := range remove.Intervals {
+		i := indexInterval(spec.Intervals, interval)
+		if i < 0 {
+			return nil, fmt.Errorf("the spec has no interval %s", schedule.FormatInterval(interval))
+		}
+		spec.Intervals = append(spec.Intervals[:i:i], spec.Intervals[i+1:]...)
+	}
+
+	add := client.ScheduleSpec{TimeZoneName: spec.TimeZoneName}
+	for _, part := range []client.ScheduleSpec{o.spec, o.addSpec} {
+		add.CronExpressions = append(add.CronExpressions, part.CronExpressions...)
+		add.Calendars = append(add.Calendars, part.Calendars...)
+		add.Intervals = append(add.Intervals, part.Intervals...)
+	}
+	if add, err = schedule.CanonicalSpec(add); err != nil {
+		return nil, err
+	}
+	spec.TimeZoneName = add.TimeZoneName
+	spec.Calendars = append(spec.Calendars, add.Calendars...)
+	spec.Intervals = append(spec.Intervals, add.Intervals...)
+	return &spec, nil
+}
+
+func patchAction(a client.ScheduleAction, o *options) (client.ScheduleAction, error) {
+	if !o.anySet("workflow-type", "workflow-id", "task-queue", "args", "memo", "remove-memo") {
+		return a, nil
+	}
+	action, ok := a.(*client.ScheduleWorkflowAction)
+	if !ok {
+		return nil, errors.New("the schedule action is not a workflow")
+	}
+	out := *action
+	if o.set["workflow-type"] {
+		out.Workflow = o.workflowType
+	}
+	if o.set["workflow-id"] {
+		out.ID = o.workflowID
+	}
+	if o.set["task-queue"] {
+		out.TaskQueue = o.taskQueue
+	}
+	if o.set["args"] {
+		out.Args = o.workflowArgs
+	}
+	if o.set["memo"] || o.set["remove-memo"] {
+		out.Memo = map[string]interface{}{}
+		for key, value := range action.Memo {
+			out.Memo[key] = value
+		}
+		for _, key := range o.removeMemo {
+			if _, ok := out.Memo[key]; !ok {
+				return nil, fmt.Errorf("the workflow memo has no key %s", key)
+			}
+			delete(out.Memo, key)
+		}
+		for key, value := range o.memoValues {
+			out.Memo[key] = value
+		}
+	}
+	return &out, nil
+}
+
+func (o *options) anySet(names ...string) bool {
+	for _, name := range names {
+		if o.set[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// clone returns a copy of *p, or a new T when p is nil.
+// It copies the policies and state of a Schedule, whose types the SDK does not export.
+func clone[T any](p *T) *T {
+	c := new(T)
+	if p != nil {
+		*c = *p
+	}
+	return c
+}
+
+// indexCalendar returns the index of the first of calendars that is written the same as calendar, or -1.
+// Both must be canonical.
+func indexCalendar(calendars []client.ScheduleCalendarSpec, calendar client.ScheduleCalendarSpec) int {
+	for i, c := range calendars {
+		if schedule.FormatCalendar(c) == schedule.FormatCalendar(calendar) {
+			return i
+		}
+	}
+	return -1
+}
+
+func indexInterval(intervals []client.ScheduleIntervalSpec, interval client.ScheduleIntervalSpec) int {
+	for i, c := range intervals {
+		if c == interval {
+			return i
+		}
+	}
+	return -1
+}
+
+// fieldChange is a field of a Schedule with the values that an update removes and adds.
+// A field with a single value that changes has that value removed and the new one added.
+type fieldChange struct {
+	Field   string   `json:"field"`
+	Removed []string `json:"removed,omitempty"`
+	Added   []string `json:"added,omitempty"`
+}
+
+type scheduleField struct {
+	name   string
+	values []string
+}
+
+// diffSchedules returns the fields that differ between old and updated, in a fixed order.
+// Workflow arguments are decoded with dc, and memo values with the default data converter, as the SDK encodes them.
+func diffSchedules(old, updated *client.Schedule, dc converter.DataConverter) ([]fieldChange, error) {
+	oldFields, err := scheduleFields(old, dc)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := scheduleFields(updated, dc)
+	if err != nil {
+		return nil, err
+	}
+	changes := []fieldChange{}
+	for i, field := range oldFields {
+		removed := subtract(field.values, newFields[i].values)
+		added := subtract(newFields[i].values, field.values)
+		if len(removed) > 0 || len(added) > 0 {
+			changes = append(changes, fieldChange{Field: field.name, Removed: removed, Added: added})
+		}
+	}
+	return changes, nil
+}
+
+func scheduleFields(s *client.Schedule, dc converter.DataConverter) ([]scheduleField, error) {
+	spec, err := schedule.CanonicalSpec(*clone(s.Spec))
+	if err != nil {
+		return nil, err
+	}
+	var intervals []string
+	for _, interval := range spec.Intervals {
+		intervals = append(intervals, schedule.FormatInterval(interval))
+	}
+	action, _ := s.Action.(*client.ScheduleWorkflowAction)
+	action = clone(action)
+	args, err := decodeArgs(action.Args, dc)
+	if err != nil {
+		return nil, err
+	}
+	encodedArgs, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+	memo, err := formatMemo(action.Memo)
+	if err != nil {
+		return nil, err
+	}
+	workflowType := ""
+	if action.Workflow != nil {
+		workflowType = fmt.Sprint(action.Workflow)
+	}
+	policy := clone(s.Policy)
+	state := clone(s.State)
+	remaining := "unlimited"
+	if state.LimitedActions {
+		remaining = strconv.Itoa(state.RemainingActions)
+	}
+	return []scheduleField{
+		{"spec.calendars", formatCalendars(spec.Calendars)},
+		{"spec.intervals", intervals},
+		{"spec.skip", formatCalendars(spec.Skip)},
+		{"spec.startAt", one(formatTime(spec.StartAt))},
+		{"spec.endAt", one(formatTime(spec.EndAt))},
+		{"spec.jitter", one(formatDuration(spec.Jitter))},
+		{"spec.timeZone", one(spec.TimeZoneName)},
+		{"action.workflowType", one(workflowType)},
+		{"action.workflowId", one(action.ID)},
+		{"action.taskQueue", one(action.TaskQueue)},
+		{"action.args", one(string(encodedArgs))},
+		{"action.memo", memo},
+		{"policies.overlap", one(schedule.FormatOverlapPolicy(policy.Overlap))},
+		{"policies.catchupWindow", one(formatDuration(policy.CatchupWindow))},
+		{"policies.pauseOnFailure", one(strconv.FormatBool(policy.PauseOnFailure))},
+		{"state.note", one(state.Note)},
+		{"state.paused", one(strconv.FormatBool(state.Paused))},
+		{"state.remainingActions", one(remaining)},
+	}, nil
+}
+
+// formatMemo returns the memo as sorted key=value pairs, with the values in JSON.
+func formatMemo(memo map[string]interface{}) ([]string, error) {
+	var pairs []string
+	for key, value := range memo {
+		values, err := decodeArgs([]interface{}{value}, converter.GetDefaultDataConverter())
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode workflow memo: %w", err)
+		}
+		encoded, err := json.Marshal(values[0])
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, key+"="+string(encoded))
+	}
+	sort.Strings(pairs)
+	return pairs, nil
+}
+
+func formatCalendars(calendars []client.ScheduleCalendarSpec) []string {
+	var s []string
+	for _, calendar := range calendars {
+		s = append(s, schedule.FormatCalendar(calendar))
+	}
+	return s
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+// one returns s as the only value of a field, or no values when s is empty.
+func one(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
+
+// subtract returns the values of a that are not in b, counting repeated values.
+func subtract(a, b []string) []string {
+	count := map[string]int{}
+	for _, s := range b {
+		count[s]++
+	}
+	var out []string
+	for _, s := range a {
+		if count[s] > 0 {
+			count[s]--
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
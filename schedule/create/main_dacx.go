@@ -6,12 +6,20 @@ import (
 
 	"github.com/temporalio/documentation-samples-go/schedule"
 	"go.temporal.io/sdk/client"
+
+	"documentation-samples-go/codec"
 )
 
 func main() {
 	ctx := context.Background()
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		log.Fatalln("Unable to create Data Converter", err)
+	}
 	temporalClient, err := client.Dial(client.Options{
-		HostPort: client.DefaultHostPort,
+		HostPort:      client.DefaultHostPort,
+		DataConverter: dataConverter,
 	})
 	if err != nil {
 		log.Fatalln("Unable to create Temporal Client", err)
@@ -56,5 +64,5 @@ tags:
 - code sample
 - schedule
 - create
-lines: 11, 22-33, 39, 41-47
+lines: 13, 30-41, 47, 49-55
 @dacx */
@@ -0,0 +1,109 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+
+	"go.temporal.io/sdk/client"
+)
+
+// cronFields are the calendar fields in the order of a 7-field cron string.
+// Day of week also accepts 7 for Sunday, as the end of a range or on its own, and month and day of week accept names.
+var cronFields = []calendarField{
+	calendarFields[0],
+	calendarFields[1],
+	calendarFields[2],
+	calendarFields[3],
+	calendarFields[4],
+	{"day_of_week", 0, 7, calendarFields[6].ranges},
+	calendarFields[5],
+}
+
+var cronNames = map[string][]string{
+	"month":       {"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"},
+	"day_of_week": {"sun", "mon", "tue", "wed", "thu", "fri", "sat"},
+}
+
+var cronShortcuts = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron returns the spec the Temporal Service makes of a cron string, which is how Describe returns it.
+// It accepts what ScheduleSpec.CronExpressions accepts:
+//
+//   - 5 fields: minute, hour, day of month, month and day of week;
+//   - 6 fields: the same followed by year;
+//   - 7 fields: second, then the same as 6 fields;
+//   - @yearly, @monthly, @weekly, @daily, @hourly and "@every interval[/offset]";
+//   - an optional "CRON_TZ=zone " prefix, which sets the time zone of the spec, and a "# comment" suffix.
+func ParseCron(s string) (client.ScheduleSpec, error) {
+	var spec client.ScheduleSpec
+	expr := strings.TrimSpace(s)
+	if strings.HasPrefix(expr, "CRON_TZ=") || strings.HasPrefix(expr, "TZ=") {
+		var zone string
+		zone, expr, _ = strings.Cut(expr, " ")
+		_, spec.TimeZoneName, _ = strings.Cut(zone, "=")
+		expr = strings.TrimSpace(expr)
+	}
+	expr, comment, _ := strings.Cut(expr, "#")
+	expr, comment = strings.TrimSpace(expr), strings.TrimSpace(comment)
+
+	if strings.HasPrefix(expr, "@every ") {
+		interval, err := ParseInterval(strings.TrimSpace(strings.TrimPrefix(expr, "@every ")))
+		if err != nil {
+			return client.ScheduleSpec{}, fmt.Errorf("invalid cron %q: %w", s, err)
+		}
+		spec.Intervals = []client.ScheduleIntervalSpec{interval}
+		return spec, nil
+	}
+	if shortcut, ok := cronShortcuts[strings.ToLower(expr)]; ok {
+		expr = shortcut
+	}
+
+	values := strings.Fields(expr)
+	switch len(values) {
+	case 5:
+		values = append([]string{"0"}, append(values, "*")...)
+	case 6:
+		values = append([]string{"0"}, values...)
+	case 7:
+	default:
+		return client.ScheduleSpec{}, fmt.Errorf("invalid cron %q: must have 5, 6 or 7 fields", s)
+	}
+	calendar := client.ScheduleCalendarSpec{Comment: comment}
+	for i, field := range cronFields {
+		value := strings.ToLower(values[i])
+		if value == "*" && field.name == "year" {
+			continue
+		}
+		if value == "?" {
+			value = "*"
+		}
+		if value == "*" && field.name == "day_of_week" {
+			field.max = 6
+		}
+		for n, name := range cronNames[field.name] {
+			if name != "" {
+				value = strings.ReplaceAll(value, name, fmt.Sprint(n))
+			}
+		}
+		ranges, err := parseRanges(field, value)
+		if err != nil {
+			return client.ScheduleSpec{}, fmt.Errorf("invalid cron %q: %w", s, err)
+		}
+		for j, r := range ranges {
+			if field.name == "day_of_week" && r.Start == 7 && r.End <= 7 {
+				ranges[j] = client.ScheduleRange{Start: 0}
+			}
+		}
+		*field.ranges(&calendar) = ranges
+	}
+	spec.Calendars = []client.ScheduleCalendarSpec{calendar}
+	return spec, nil
+}
@@ -0,0 +1,61 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/client"
+)
+
+func TestParseCron(t *testing.T) {
+	spec, err := ParseCron("CRON_TZ=Europe/Paris 30 9 * JAN-MAR mon-fri # weekday mornings")
+	require.NoError(t, err)
+	require.Equal(t, "Europe/Paris", spec.TimeZoneName)
+	require.Equal(t, []client.ScheduleCalendarSpec{{
+		Second:     []client.ScheduleRange{{Start: 0}},
+		Minute:     []client.ScheduleRange{{Start: 30}},
+		Hour:       []client.ScheduleRange{{Start: 9}},
+		DayOfMonth: []client.ScheduleRange{{Start: 1, End: 31}},
+		Month:      []client.ScheduleRange{{Start: 1, End: 3}},
+		DayOfWeek:  []client.ScheduleRange{{Start: 1, End: 5}},
+		Comment:    "weekday mornings",
+	}}, spec.Calendars)
+
+	spec, err = ParseCron("15 0 0 1 * ? 2030")
+	require.NoError(t, err)
+	require.Equal(t, []client.ScheduleRange{{Start: 15}}, spec.Calendars[0].Second)
+	require.Equal(t, []client.ScheduleRange{{Start: 2030}}, spec.Calendars[0].Year)
+
+	spec, err = ParseCron("@every 1h/5m")
+	require.NoError(t, err)
+	require.Equal(t, []client.ScheduleIntervalSpec{{Every: time.Hour, Offset: 5 * time.Minute}}, spec.Intervals)
+
+	spec, err = ParseCron("@daily")
+	require.NoError(t, err)
+	require.Equal(t, "second=0;minute=0;hour=0;day_of_month=1-31;month=1-12;day_of_week=0-6", FormatCalendar(spec.Calendars[0]))
+
+	for _, in := range []string{"", "* * *", "60 * * * *", "* * * * 8", "@every never"} {
+		_, err := ParseCron(in)
+		require.Error(t, err, in)
+	}
+}
+
+func TestCanonicalSpec(t *testing.T) {
+	fromCron, err := CanonicalSpec(client.ScheduleSpec{CronExpressions: []string{"30 9 * * 1-5"}})
+	require.NoError(t, err)
+	fromCalendar, err := CanonicalSpec(client.ScheduleSpec{Calendars: []client.ScheduleCalendarSpec{{
+		Minute:    []client.ScheduleRange{{Start: 30}},
+		Hour:      []client.ScheduleRange{{Start: 9}},
+		DayOfWeek: []client.ScheduleRange{{Start: 1, End: 5, Step: 1}},
+	}}})
+	require.NoError(t, err)
+	require.Equal(t, fromCalendar, fromCron)
+	require.Empty(t, fromCron.CronExpressions)
+
+	_, err = CanonicalSpec(client.ScheduleSpec{
+		CronExpressions: []string{"CRON_TZ=Asia/Tokyo 0 0 * * *"},
+		TimeZoneName:    "UTC",
+	})
+	require.Error(t, err)
+}
@@ -5,12 +5,20 @@ import (
 	"log"
 
 	"go.temporal.io/sdk/client"
+
+	"documentation-samples-go/codec"
 )
 
 func main() {
 	ctx := context.Background()
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		log.Fatalln("Unable to create Data Converter", err)
+	}
 	temporalClient, err := client.Dial(client.Options{
-		HostPort: client.DefaultHostPort,
+		HostPort:      client.DefaultHostPort,
+		DataConverter: dataConverter,
 	})
 	if err != nil {
 		log.Fatalln("Unable to create Temporal Client", err)
@@ -48,5 +56,5 @@ tags:
 - code sample
 - schedule
 - delete
-lines: 10, 25-31, 34-39
+lines: 12, 33-39, 42-47
 @dacx */
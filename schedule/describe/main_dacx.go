@@ -5,14 +5,22 @@ import (
 	"log"
 
 	"go.temporal.io/sdk/client"
+
+	"documentation-samples-go/codec"
 )
 
 func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		log.Fatalln("Unable to create Data Converter", err)
+	}
 	temporalClient, err := client.Dial(client.Options{
-		HostPort: client.DefaultHostPort,
+		HostPort:      client.DefaultHostPort,
+		DataConverter: dataConverter,
 	})
 	if err != nil {
 		log.Fatalln("Unable to create Temporal Client", err)
@@ -49,5 +57,5 @@ tags:
 - code sample
 - schedule
 - describe
-lines: 10, 32, 35-40
+lines: 12, 40, 43-48
 @dacx */
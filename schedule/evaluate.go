@@ -0,0 +1,257 @@
+package schedule
+
+import (
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/client"
+)
+
+// maxCalendarYear is the last year in which the Temporal Service looks for action times.
+const maxCalendarYear = 2100
+
+// ActionTime is when a Schedule takes an Action.
+// Nominal is the time the spec matches. Jitter delays the Action to a time between Nominal and Latest,
+// which the Temporal Service picks and Describe returns in NextActionTimes.
+type ActionTime struct {
+	Nominal time.Time `json:"nominal"`
+	Latest  time.Time `json:"latest"`
+}
+
+// Evaluator computes the action times of a ScheduleSpec the way the Temporal Service does, without contacting it:
+//
+//   - a time matches when it matches any calendar or interval, and no skip calendar;
+//   - calendars match in the time zone of the spec, and intervals count from the Unix epoch plus their offset;
+//   - action times are whole seconds, no earlier than StartAt and no later than EndAt;
+//   - jitter is at most the spec's Jitter, and never reaches the next matching time.
+type Evaluator struct {
+	spec      client.ScheduleSpec
+	loc       *time.Location
+	calendars []client.ScheduleCalendarSpec
+	skip      []client.ScheduleCalendarSpec
+}
+
+// NewEvaluator returns an Evaluator of spec. Cron expressions are turned into calendars and intervals, as the Temporal Service does.
+func NewEvaluator(spec client.ScheduleSpec) (*Evaluator, error) {
+	canonical, err := CanonicalSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(canonical.TimeZoneName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %w", canonical.TimeZoneName, err)
+	}
+	for _, interval := range canonical.Intervals {
+		if interval.Every < time.Second {
+			return nil, fmt.Errorf("invalid interval %s: must be at least a second", FormatInterval(interval))
+		}
+	}
+	return &Evaluator{
+		spec:      canonical,
+		loc:       loc,
+		calendars: canonical.Calendars,
+		skip:      canonical.Skip,
+	}, nil
+}
+
+// Location returns the time zone of the spec. Action times are in this time zone.
+func (e *Evaluator) Location() *time.Location {
+	return e.loc
+}
+
+// Next returns the first action time after after. It returns false when there is none, because the spec has ended or matches nothing.
+func (e *Evaluator) Next(after time.Time) (ActionTime, bool) {
+	// A time equal to StartAt is an action time.
+	if start := e.spec.StartAt.Add(-time.Second); !e.spec.StartAt.IsZero() && after.Before(start) {
+		after = start
+	}
+	var nominal time.Time
+	for nominal.IsZero() || e.skipped(nominal) {
+		nominal = e.rawNext(after)
+		if nominal.IsZero() || e.ended(nominal) {
+			return ActionTime{}, false
+		}
+		after = nominal
+	}
+	jitter := e.spec.Jitter
+	if following := e.rawNext(nominal); !following.IsZero() && following.Sub(nominal) < jitter {
+		jitter = following.Sub(nominal)
+	}
+	return ActionTime{Nominal: nominal, Latest: nominal.Add(jitter)}, true
+}
+
+// NextN returns the first n action times after after. It returns fewer when the spec ends.
+func (e *Evaluator) NextN(after time.Time, n int) []ActionTime {
+	var times []ActionTime
+	for len(times) < n {
+		t, ok := e.Next(after)
+		if !ok {
+			break
+		}
+		times = append(times, t)
+		after = t.Nominal
+	}
+	return times
+}
+
+// Between returns the action times whose nominal time is at or after start and before end.
+func (e *Evaluator) Between(start, end time.Time) []ActionTime {
+	var times []ActionTime
+	after := start.Add(-time.Second)
+	for {
+		t, ok := e.Next(after)
+		if !ok || !t.Nominal.Before(end) {
+			return times
+		}
+		times = append(times, t)
+		after = t.Nominal
+	}
+}
+
+// rawNext returns the first time after after that matches a calendar or an interval, skipped or not.
+func (e *Evaluator) rawNext(after time.Time) time.Time {
+	var next time.Time
+	for i := range e.calendars {
+		if t := e.nextCalendarTime(&e.calendars[i], after); !t.IsZero() && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+	for _, interval := range e.spec.Intervals {
+		every := int64(interval.Every / time.Second)
+		offset := int64(interval.Offset / time.Second)
+		ts := ((after.Unix()-offset)/every+1)*every + offset
+		if t := time.Unix(ts, 0).In(e.loc); next.IsZero() || t.Before(next) {
+			next = t
+		}
+	}
+	return next
+}
+
+func (e *Evaluator) ended(t time.Time) bool {
+	return (!e.spec.EndAt.IsZero() && t.After(e.spec.EndAt)) || t.Year() > maxCalendarYear
+}
+
+func (e *Evaluator) skipped(t time.Time) bool {
+	for i := range e.skip {
+		if e.matches(&e.skip[i], t) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether t, in the time zone of the spec, matches every field of calendar.
+func (e *Evaluator) matches(calendar *client.ScheduleCalendarSpec, t time.Time) bool {
+	t = t.In(e.loc)
+	return matchRanges(calendar.Year, t.Year()) &&
+		matchRanges(calendar.Month, int(t.Month())) &&
+		matchRanges(calendar.DayOfMonth, t.Day()) &&
+		matchDayOfWeek(calendar.DayOfWeek, t.Weekday()) &&
+		matchRanges(calendar.Hour, t.Hour()) &&
+		matchRanges(calendar.Minute, t.Minute()) &&
+		matchRanges(calendar.Second, t.Second())
+}
+
+// nextCalendarTime returns the first time after after that matches calendar, or the zero time if there is none before maxCalendarYear.
+// It walks the wall clock of the spec's time zone as the Temporal Service does, carrying each field into the next.
+// That walk decides what happens around a daylight saving change: wall clock times that the change skips don't match,
+// and when the walk carries out of the hour before a repeated hour, that hour is taken at its first occurrence only.
+func (e *Evaluator) nextCalendarTime(calendar *client.ScheduleCalendarSpec, after time.Time) time.Time {
+	after = after.In(e.loc)
+	y, mo, d := after.Date()
+	h, mi, s := after.Clock()
+	dst := time.Duration(0)
+	if after.Add(-time.Hour).Hour() == h {
+		// after is in the second occurrence of a repeated hour.
+		dst = time.Hour
+	}
+	s++
+	for {
+		if s >= 60 {
+			mi, s = mi+1, 0
+		}
+		if mi >= 60 {
+			prev := time.Date(y, mo, d, h, 0, 0, 0, e.loc)
+			h, mi = h+1, 0
+			// time.Date takes the second occurrence of a repeated wall clock time, so an hour that seems to last two
+			// hours is followed by a repeated one: walk its hour again, an hour earlier.
+			if next := time.Date(y, mo, d, h, 0, 0, 0, e.loc); dst == 0 && next.Sub(prev) > time.Hour {
+				h, dst = h-1, time.Hour
+			} else {
+				dst = 0
+			}
+		}
+		if h >= 24 {
+			d, h = d+1, 0
+		}
+		if d > daysIn(mo, y) {
+			mo, d = mo+1, 1
+		}
+		if mo > time.December {
+			y, mo = y+1, time.January
+		}
+		if y > maxCalendarYear {
+			return time.Time{}
+		}
+		if !matchRanges(calendar.Year, y) {
+			y, mo, d, h, mi, s, dst = y+1, time.January, 1, 0, 0, 0, 0
+			continue
+		}
+		if !matchRanges(calendar.Month, int(mo)) {
+			mo, d, h, mi, s, dst = mo+1, 1, 0, 0, 0, 0
+			continue
+		}
+		if !matchRanges(calendar.DayOfMonth, d) || !matchDayOfWeek(calendar.DayOfWeek, time.Date(y, mo, d, 0, 0, 0, 0, time.UTC).Weekday()) {
+			d, h, mi, s, dst = d+1, 0, 0, 0, 0
+			continue
+		}
+		if !matchRanges(calendar.Hour, h) {
+			h, mi, s, dst = h+1, 0, 0, 0
+			continue
+		}
+		if !matchRanges(calendar.Minute, mi) {
+			mi, s = mi+1, 0
+			continue
+		}
+		if !matchRanges(calendar.Second, s) {
+			s++
+			continue
+		}
+		t := time.Date(y, mo, d, h, mi, s, 0, e.loc)
+		if t.Hour() != h {
+			// A daylight saving change skips this wall clock time.
+			h, mi, s = h+1, 0, 0
+			continue
+		}
+		return t.Add(dst)
+	}
+}
+
+func daysIn(month time.Month, year int) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// matchRanges reports whether value is in ranges. No ranges match every value.
+func matchRanges(ranges []client.ScheduleRange, value int) bool {
+	if len(ranges) == 0 {
+		return true
+	}
+	for _, r := range ranges {
+		end, step := r.End, r.Step
+		if end < r.Start {
+			end = r.Start
+		}
+		if step < 1 {
+			step = 1
+		}
+		if value >= r.Start && value <= end && (value-r.Start)%step == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// matchDayOfWeek is matchRanges for days of the week, where 7 is also Sunday.
+func matchDayOfWeek(ranges []client.ScheduleRange, weekday time.Weekday) bool {
+	return matchRanges(ranges, int(weekday)) || (weekday == time.Sunday && matchRanges(ranges, 7))
+}
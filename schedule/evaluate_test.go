@@ -0,0 +1,227 @@
+package schedule
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/stretchr/testify/require"
+	schedulepb "go.temporal.io/api/schedule/v1"
+	"go.temporal.io/sdk/client"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func nominalTimes(times []ActionTime) []string {
+	var out []string
+	for _, t := range times {
+		out = append(out, t.Nominal.UTC().Format(time.RFC3339))
+	}
+	return out
+}
+
+func TestEvaluatorNextN(t *testing.T) {
+	e, err := NewEvaluator(client.ScheduleSpec{CronExpressions: []string{"0 12 * * *"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"2023-01-01T12:00:00Z",
+		"2023-01-02T12:00:00Z",
+		"2023-01-03T12:00:00Z",
+	}, nominalTimes(e.NextN(date("2023-01-01T00:00:00Z"), 3)))
+
+	// The first action time is strictly after the given time.
+	next, ok := e.Next(date("2023-01-01T12:00:00Z"))
+	require.True(t, ok)
+	require.Equal(t, date("2023-01-02T12:00:00Z"), next.Nominal.UTC())
+}
+
+func TestEvaluatorBetween(t *testing.T) {
+	e, err := NewEvaluator(client.ScheduleSpec{
+		Intervals: []client.ScheduleIntervalSpec{{Every: 30 * time.Minute, Offset: 5 * time.Minute}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"2023-01-01T00:05:00Z",
+		"2023-01-01T00:35:00Z",
+		"2023-01-01T01:05:00Z",
+	}, nominalTimes(e.Between(date("2023-01-01T00:05:00Z"), date("2023-01-01T01:35:00Z"))))
+}
+
+func TestEvaluatorStartAndEnd(t *testing.T) {
+	e, err := NewEvaluator(client.ScheduleSpec{
+		CronExpressions: []string{"@hourly"},
+		StartAt:         date("2023-01-01T10:00:00Z"),
+		EndAt:           date("2023-01-01T12:00:00Z"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"2023-01-01T10:00:00Z",
+		"2023-01-01T11:00:00Z",
+		"2023-01-01T12:00:00Z",
+	}, nominalTimes(e.NextN(date("2023-01-01T00:00:00Z"), 10)))
+}
+
+func TestEvaluatorSkip(t *testing.T) {
+	e, err := NewEvaluator(client.ScheduleSpec{
+		CronExpressions: []string{"0 0 * * *"},
+		Skip:            []client.ScheduleCalendarSpec{{DayOfWeek: []client.ScheduleRange{{Start: 0}, {Start: 6}}}},
+	})
+	require.NoError(t, err)
+	// 2023-01-06 is a Friday.
+	require.Equal(t, []string{
+		"2023-01-06T00:00:00Z",
+		"2023-01-09T00:00:00Z",
+	}, nominalTimes(e.NextN(date("2023-01-05T12:00:00Z"), 2)))
+}
+
+func TestEvaluatorTimeZone(t *testing.T) {
+	e, err := NewEvaluator(client.ScheduleSpec{CronExpressions: []string{"CRON_TZ=Europe/Paris 0 2 * * *"}})
+	require.NoError(t, err)
+	require.Equal(t, "Europe/Paris", e.Location().String())
+	// Europe/Paris moves from UTC+1 to UTC+2 at 02:00 on 2023-03-26, so 02:00 doesn't happen that day.
+	require.Equal(t, []string{
+		"2023-03-25T01:00:00Z",
+		"2023-03-27T00:00:00Z",
+		"2023-03-28T00:00:00Z",
+	}, nominalTimes(e.NextN(date("2023-03-24T12:00:00Z"), 3)))
+}
+
+func TestEvaluatorJitter(t *testing.T) {
+	e, err := NewEvaluator(client.ScheduleSpec{
+		Intervals: []client.ScheduleIntervalSpec{{Every: time.Minute}},
+		Jitter:    time.Hour,
+	})
+	require.NoError(t, err)
+	next, ok := e.Next(date("2023-01-01T00:00:30Z"))
+	require.True(t, ok)
+	// Jitter is capped at the gap to the next action time, so a delayed action stays before it.
+	require.Equal(t, time.Minute, next.Latest.Sub(next.Nominal))
+}
+
+func TestEvaluatorErrors(t *testing.T) {
+	_, err := NewEvaluator(client.ScheduleSpec{TimeZoneName: "Mars/Olympus_Mons"})
+	require.Error(t, err)
+	_, err = NewEvaluator(client.ScheduleSpec{CronExpressions: []string{"* *"}})
+	require.Error(t, err)
+
+	e, err := NewEvaluator(client.ScheduleSpec{})
+	require.NoError(t, err)
+	_, ok := e.Next(time.Now())
+	require.False(t, ok)
+}
+
+// TestEvaluatorExpectedTimes checks the Evaluator against the hand-written cases in testdata/expected.
+// They guard its reading of the scheduling rules against regressions, and do not show that the Temporal Service agrees.
+func TestEvaluatorExpectedTimes(t *testing.T) {
+	files, err := filepath.Glob("testdata/expected/*.json")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			checkFutureActionTimes(t, file)
+		})
+	}
+}
+
+// TestEvaluatorMatchesDescribe checks the Evaluator against the output of `temporal schedule describe -o json`
+// recorded in testdata/describe, as testdata/expected/README.md explains.
+func TestEvaluatorMatchesDescribe(t *testing.T) {
+	files, err := filepath.Glob("testdata/describe/*.json")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			checkFutureActionTimes(t, file)
+		})
+	}
+}
+
+// checkFutureActionTimes reads a DescribeScheduleResponse in protobuf JSON from file, and checks that the Evaluator
+// predicts its info.futureActionTimes from its schedule.spec, starting at its info.createTime.
+// Only schedule.spec and info are parsed: newer CLIs spell enums such as the task queue kind in a form that this
+// version of the API does not read, and neither part holds an enum.
+func checkFutureActionTimes(t *testing.T, file string) {
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	var resp struct {
+		Schedule struct {
+			Spec json.RawMessage `json:"spec"`
+		} `json:"schedule"`
+		Info json.RawMessage `json:"info"`
+	}
+	require.NoError(t, json.Unmarshal(data, &resp))
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	var spec schedulepb.ScheduleSpec
+	require.NoError(t, unmarshaler.Unmarshal(bytes.NewReader(resp.Schedule.Spec), &spec))
+	var info schedulepb.ScheduleInfo
+	require.NoError(t, unmarshaler.Unmarshal(bytes.NewReader(resp.Info), &info))
+
+	e, err := NewEvaluator(specFromPB(&spec))
+	require.NoError(t, err)
+	want := info.FutureActionTimes
+	got := e.NextN(*info.CreateTime, len(want))
+	require.Len(t, got, len(want))
+	for i, w := range want {
+		require.False(t, w.Before(got[i].Nominal), "action %d: %s is before %s", i, w, got[i].Nominal)
+		require.False(t, w.After(got[i].Latest), "action %d: %s is after %s", i, w, got[i].Latest)
+	}
+}
+
+func specFromPB(spec *schedulepb.ScheduleSpec) client.ScheduleSpec {
+	calendars := func(specs []*schedulepb.StructuredCalendarSpec) []client.ScheduleCalendarSpec {
+		var out []client.ScheduleCalendarSpec
+		for _, c := range specs {
+			out = append(out, client.ScheduleCalendarSpec{
+				Second:     rangesFromPB(c.Second),
+				Minute:     rangesFromPB(c.Minute),
+				Hour:       rangesFromPB(c.Hour),
+				DayOfMonth: rangesFromPB(c.DayOfMonth),
+				Month:      rangesFromPB(c.Month),
+				Year:       rangesFromPB(c.Year),
+				DayOfWeek:  rangesFromPB(c.DayOfWeek),
+			})
+		}
+		return out
+	}
+	out := client.ScheduleSpec{
+		Calendars:       calendars(spec.StructuredCalendar),
+		CronExpressions: spec.CronString,
+		Skip:            calendars(spec.ExcludeStructuredCalendar),
+		TimeZoneName:    spec.TimezoneName,
+	}
+	for _, interval := range spec.Interval {
+		out.Intervals = append(out.Intervals, client.ScheduleIntervalSpec{Every: *interval.Interval})
+		if interval.Phase != nil {
+			out.Intervals[len(out.Intervals)-1].Offset = *interval.Phase
+		}
+	}
+	if spec.StartTime != nil {
+		out.StartAt = *spec.StartTime
+	}
+	if spec.EndTime != nil {
+		out.EndAt = *spec.EndTime
+	}
+	if spec.Jitter != nil {
+		out.Jitter = *spec.Jitter
+	}
+	return out
+}
+
+func rangesFromPB(ranges []*schedulepb.Range) []client.ScheduleRange {
+	var out []client.ScheduleRange
+	for _, r := range ranges {
+		out = append(out, client.ScheduleRange{Start: int(r.Start), End: int(r.End), Step: int(r.Step)})
+	}
+	return out
+}
@@ -0,0 +1,240 @@
+// Package gaps finds the action times a Schedule missed and backfills only those.
+//
+// The Planner works out the times a Schedule should have started Workflows in a time range, lists the Workflow Executions
+// that the Schedule started in that range by their TemporalScheduledById and TemporalScheduledStartTime Search Attributes,
+// and groups the times with no Execution into Windows. Applying the Plan backfills each Window with its own overlap policy.
+package gaps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"google.golang.org/grpc"
+
+	"github.com/temporalio/documentation-samples-go/schedule"
+)
+
+// Visibility lists Workflow Executions. client.Client implements it.
+type Visibility interface {
+	ListWorkflow(ctx context.Context, request *workflowservice.ListWorkflowExecutionsRequest) (*workflowservice.ListWorkflowExecutionsResponse, error)
+}
+
+// Namespaces describes Namespaces. The WorkflowService of a client.Client implements it.
+type Namespaces interface {
+	DescribeNamespace(ctx context.Context, request *workflowservice.DescribeNamespaceRequest, opts ...grpc.CallOption) (*workflowservice.DescribeNamespaceResponse, error)
+}
+
+// Window is a run of consecutive missed action times, which one client.ScheduleBackfill covers.
+type Window struct {
+	Times   []time.Time
+	Overlap enums.ScheduleOverlapPolicy
+}
+
+// Backfill returns the backfill of the Window.
+// A backfill takes the actions after its Start and up to its End, so Start is just before the first missed time.
+func (w Window) Backfill() client.ScheduleBackfill {
+	return client.ScheduleBackfill{
+		Start:   w.Times[0].Add(-time.Millisecond),
+		End:     w.Times[len(w.Times)-1],
+		Overlap: w.Overlap,
+	}
+}
+
+// Plan is the result of comparing the action times of a Schedule with the Workflow Executions it started.
+type Plan struct {
+	ScheduleID string
+	Start, End time.Time
+	// Expected are the action times of the Schedule at or after Start and before End.
+	Expected []time.Time
+	// Missing are the Expected times with no Workflow Execution.
+	Missing []time.Time
+	// Windows group the Missing times. Their overlap policy is unspecified until the caller sets it.
+	Windows []Window
+}
+
+// SettleMargin is how long after an action time, on top of the jitter of the Schedule, Plan waits before it expects
+// a Workflow Execution for it. Until then the Execution may not have started, or may not be in Visibility yet.
+const SettleMargin = 10 * time.Second
+
+// ErrBeyondRetention is returned by Plan when the range starts before the retention period of the Namespace.
+var ErrBeyondRetention = errors.New("range starts before the retention period: workflows that closed then are no longer listed, so their action times would be backfilled again")
+
+// Planner makes and applies Plans.
+type Planner struct {
+	Schedules client.ScheduleClient
+	Workflows Visibility
+	// Namespaces describes Namespace, the Namespace of the Schedules, to find its retention period.
+	// Visibility no longer lists the Workflow Executions that closed before it,
+	// so Plan refuses ranges that start earlier, whose action times would all look missed.
+	Namespaces Namespaces
+	Namespace  string
+	// BeyondRetention lets Plan take ranges that start before the retention period, without describing the Namespace.
+	// Only set it when the Workflows of the Schedule are kept longer, for example in an archive that Workflows lists.
+	BeyondRetention bool
+	// Now returns the current time. Action times later than it less the jitter and SettleMargin are not expected yet.
+	// It defaults to time.Now.
+	Now func() time.Time
+}
+
+// Plan finds the action times of the Schedule scheduleID at or after start and before end that have no Workflow Execution.
+// The start is moved forward to when the Schedule was created when it is earlier, since the Schedule took no actions before.
+// The end is moved back to now less the jitter of the Schedule and SettleMargin when it is later,
+// so that actions that are delayed or have just started are not taken for missed ones.
+// Unless BeyondRetention is set, Plan returns an error when the start is before the retention period of the Namespace.
+func (p *Planner) Plan(ctx context.Context, scheduleID string, start, end time.Time) (*Plan, error) {
+	now := time.Now
+	if p.Now != nil {
+		now = p.Now
+	}
+	desc, err := p.Schedules.GetHandle(ctx, scheduleID).Describe(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to describe schedule %s: %w", scheduleID, err)
+	}
+	var spec client.ScheduleSpec
+	if desc.Schedule.Spec != nil {
+		spec = *desc.Schedule.Spec
+	}
+	if created := desc.Info.CreatedAt; !created.IsZero() && start.Before(created) {
+		start = created
+	}
+	if settled := now().Add(-spec.Jitter - SettleMargin); end.After(settled) {
+		end = settled
+	}
+	if !p.BeyondRetention {
+		err := p.checkRetention(ctx, start, now())
+		if err != nil {
+			return nil, err
+		}
+	}
+	evaluator, err := schedule.NewEvaluator(spec)
+	if err != nil {
+		return nil, fmt.Errorf("schedule %s: %w", scheduleID, err)
+	}
+	plan := &Plan{ScheduleID: scheduleID, Start: start, End: end}
+	for _, t := range evaluator.Between(start, end) {
+		plan.Expected = append(plan.Expected, t.Nominal)
+	}
+	if len(plan.Expected) == 0 {
+		return plan, nil
+	}
+
+	started, err := p.startTimes(ctx, scheduleID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	plan.Missing = Missing(plan.Expected, started)
+	plan.Windows = Windows(plan.Expected, plan.Missing)
+	return plan, nil
+}
+
+// Apply backfills the Windows of plan, one Backfill call per Window, and stops at the first one that fails.
+func (p *Planner) Apply(ctx context.Context, plan *Plan) error {
+	handle := p.Schedules.GetHandle(ctx, plan.ScheduleID)
+	for i, window := range plan.Windows {
+		err := handle.Backfill(ctx, client.ScheduleBackfillOptions{Backfill: []client.ScheduleBackfill{window.Backfill()}})
+		if err != nil {
+			return fmt.Errorf("unable to backfill window %d of schedule %s: %w", i+1, plan.ScheduleID, err)
+		}
+	}
+	return nil
+}
+
+// checkRetention returns an error when start is before the retention period of the Namespace at now.
+func (p *Planner) checkRetention(ctx context.Context, start, now time.Time) error {
+	if p.Namespaces == nil {
+		return fmt.Errorf("unable to check the retention period of namespace %q: no Namespaces given", p.Namespace)
+	}
+	resp, err := p.Namespaces.DescribeNamespace(ctx, &workflowservice.DescribeNamespaceRequest{Namespace: p.Namespace})
+	if err != nil {
+		return fmt.Errorf("unable to describe namespace %q: %w", p.Namespace, err)
+	}
+	retention := resp.GetConfig().GetWorkflowExecutionRetentionTtl()
+	if retention == nil || *retention <= 0 {
+		return nil
+	}
+	if oldest := now.Add(-*retention); start.Before(oldest) {
+		return fmt.Errorf("%w: it starts at %s, and the %s retention period of namespace %q began at %s",
+			ErrBeyondRetention, start.Format(time.RFC3339), *retention, p.Namespace, oldest.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// startTimes returns the scheduled start times of the Workflow Executions that the Schedule started at or after start and before end,
+// whether they are running or closed.
+func (p *Planner) startTimes(ctx context.Context, scheduleID string, start, end time.Time) ([]time.Time, error) {
+	query := fmt.Sprintf("%s = %q AND %s >= %q AND %s < %q",
+		schedule.ScheduledByIDSearchAttribute, scheduleID,
+		schedule.ScheduledStartTimeSearchAttribute, start.UTC().Format(time.RFC3339Nano),
+		schedule.ScheduledStartTimeSearchAttribute, end.UTC().Format(time.RFC3339Nano))
+	var times []time.Time
+	var token []byte
+	for {
+		resp, err := p.Workflows.ListWorkflow(ctx, &workflowservice.ListWorkflowExecutionsRequest{
+			Query:         query,
+			NextPageToken: token,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to list workflows of schedule %s: %w", scheduleID, err)
+		}
+		for _, execution := range resp.Executions {
+			payload, ok := execution.GetSearchAttributes().GetIndexedFields()[schedule.ScheduledStartTimeSearchAttribute]
+			if !ok {
+				continue
+			}
+			// Search Attributes are not encoded by custom Data Converters.
+			var t time.Time
+			if err := converter.GetDefaultDataConverter().FromPayload(payload, &t); err != nil {
+				return nil, fmt.Errorf("workflow %s: unable to decode %s: %w",
+					execution.GetExecution().GetWorkflowId(), schedule.ScheduledStartTimeSearchAttribute, err)
+			}
+			times = append(times, t)
+		}
+		token = resp.NextPageToken
+		if len(token) == 0 {
+			return times, nil
+		}
+	}
+}
+
+// Missing returns the expected times that are not in started.
+func Missing(expected, started []time.Time) []time.Time {
+	seen := map[int64]bool{}
+	for _, t := range started {
+		seen[t.UnixNano()] = true
+	}
+	var missing []time.Time
+	for _, t := range expected {
+		if !seen[t.UnixNano()] {
+			missing = append(missing, t)
+		}
+	}
+	return missing
+}
+
+// Windows groups missing times that are consecutive in expected, so that a backfill of a Window takes no action that did happen.
+func Windows(expected, missing []time.Time) []Window {
+	isMissing := map[int64]bool{}
+	for _, t := range missing {
+		isMissing[t.UnixNano()] = true
+	}
+	var windows []Window
+	var current *Window
+	for _, t := range expected {
+		if !isMissing[t.UnixNano()] {
+			current = nil
+			continue
+		}
+		if current == nil {
+			windows = append(windows, Window{})
+			current = &windows[len(windows)-1]
+		}
+		current.Times = append(current.Times, t)
+	}
+	return windows
+}
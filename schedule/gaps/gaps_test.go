@@ -0,0 +1,251 @@
+package gaps
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/api/enums/v1"
+	namespacepb "go.temporal.io/api/namespace/v1"
+	workflowpb "go.temporal.io/api/workflow/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"google.golang.org/grpc"
+
+	"github.com/temporalio/documentation-samples-go/schedule"
+	"github.com/temporalio/documentation-samples-go/schedule/internal/scheduletest"
+)
+
+// newSchedules returns a ScheduleClient with the Schedule id of spec, created on 2022-12-01.
+func newSchedules(t *testing.T, id string, spec client.ScheduleSpec) *scheduletest.ScheduleClient {
+	schedules := scheduletest.NewScheduleClient()
+	schedules.Now = func() time.Time { return scheduletest.Date("2022-12-01T00:00:00Z") }
+	_, err := schedules.Create(context.Background(), client.ScheduleOptions{
+		ID:     id,
+		Spec:   spec,
+		Action: &client.ScheduleWorkflowAction{Workflow: "ScheduleWorkflow", TaskQueue: "schedule"},
+	})
+	require.NoError(t, err)
+	return schedules
+}
+
+// fakeVisibility returns Executions with the given scheduled start times, one per page, and records the queries.
+type fakeVisibility struct {
+	startTimes []time.Time
+	queries    []string
+}
+
+func (f *fakeVisibility) ListWorkflow(_ context.Context, request *workflowservice.ListWorkflowExecutionsRequest) (*workflowservice.ListWorkflowExecutionsResponse, error) {
+	f.queries = append(f.queries, request.Query)
+	page := 0
+	if len(request.NextPageToken) > 0 {
+		page = int(request.NextPageToken[0])
+	}
+	if page >= len(f.startTimes) {
+		return &workflowservice.ListWorkflowExecutionsResponse{}, nil
+	}
+	payload, err := converter.GetDefaultDataConverter().ToPayload(f.startTimes[page])
+	if err != nil {
+		return nil, err
+	}
+	resp := &workflowservice.ListWorkflowExecutionsResponse{
+		Executions: []*workflowpb.WorkflowExecutionInfo{{
+			SearchAttributes: &commonpb.SearchAttributes{IndexedFields: map[string]*commonpb.Payload{
+				schedule.ScheduledStartTimeSearchAttribute: payload,
+			}},
+		}},
+	}
+	if page+1 < len(f.startTimes) {
+		resp.NextPageToken = []byte{byte(page + 1)}
+	}
+	return resp, nil
+}
+
+// fakeNamespaces describes every Namespace with a retention period, and records the Namespaces described.
+type fakeNamespaces struct {
+	retention time.Duration
+	described []string
+}
+
+func (f *fakeNamespaces) DescribeNamespace(_ context.Context, request *workflowservice.DescribeNamespaceRequest, _ ...grpc.CallOption) (*workflowservice.DescribeNamespaceResponse, error) {
+	f.described = append(f.described, request.Namespace)
+	return &workflowservice.DescribeNamespaceResponse{
+		Config: &namespacepb.NamespaceConfig{WorkflowExecutionRetentionTtl: &f.retention},
+	}, nil
+}
+
+// thirtyDays is the retention period of the Namespace in the tests.
+var thirtyDays = &fakeNamespaces{retention: 30 * 24 * time.Hour}
+
+func TestWindows(t *testing.T) {
+	expected := scheduletest.Dates("2023-01-01T01:00:00Z", "2023-01-01T02:00:00Z", "2023-01-01T03:00:00Z", "2023-01-01T04:00:00Z", "2023-01-01T05:00:00Z")
+	started := scheduletest.Dates("2023-01-01T03:00:00Z")
+	missing := Missing(expected, started)
+	require.Equal(t, scheduletest.Dates("2023-01-01T01:00:00Z", "2023-01-01T02:00:00Z", "2023-01-01T04:00:00Z", "2023-01-01T05:00:00Z"), missing)
+
+	windows := Windows(expected, missing)
+	require.Equal(t, []Window{
+		{Times: scheduletest.Dates("2023-01-01T01:00:00Z", "2023-01-01T02:00:00Z")},
+		{Times: scheduletest.Dates("2023-01-01T04:00:00Z", "2023-01-01T05:00:00Z")},
+	}, windows)
+	require.Equal(t, client.ScheduleBackfill{
+		Start: scheduletest.Date("2023-01-01T00:59:59.999Z"),
+		End:   scheduletest.Date("2023-01-01T02:00:00Z"),
+	}, windows[0].Backfill())
+
+	require.Empty(t, Windows(expected, nil))
+}
+
+func TestPlanAndApply(t *testing.T) {
+	ctx := context.Background()
+	schedules := newSchedules(t, "hourly", client.ScheduleSpec{CronExpressions: []string{"0 * * * *"}})
+	workflows := &fakeVisibility{startTimes: scheduletest.Dates("2023-01-01T00:00:00Z", "2023-01-01T03:00:00Z", "2023-01-01T04:00:00Z")}
+	planner := &Planner{
+		Schedules:  schedules,
+		Workflows:  workflows,
+		Namespaces: thirtyDays,
+		Namespace:  "default",
+		Now:        func() time.Time { return scheduletest.Date("2023-01-01T06:30:00Z") },
+	}
+
+	// The end of the range is after now, so it is moved back to now less SettleMargin.
+	plan, err := planner.Plan(ctx, "hourly", scheduletest.Date("2023-01-01T00:00:00Z"), scheduletest.Date("2023-01-02T00:00:00Z"))
+	require.NoError(t, err)
+	require.Len(t, plan.Expected, 7)
+	require.Equal(t, scheduletest.Dates("2023-01-01T01:00:00Z", "2023-01-01T02:00:00Z", "2023-01-01T05:00:00Z", "2023-01-01T06:00:00Z"), plan.Missing)
+	require.Len(t, plan.Windows, 2)
+	require.Len(t, workflows.queries, 3, "one query per page")
+	require.Equal(t, `TemporalScheduledById = "hourly" AND TemporalScheduledStartTime >= "2023-01-01T00:00:00Z" AND TemporalScheduledStartTime < "2023-01-01T06:29:50Z"`, workflows.queries[0])
+
+	plan.Windows[1].Overlap = enums.SCHEDULE_OVERLAP_POLICY_ALLOW_ALL
+	require.NoError(t, planner.Apply(ctx, plan))
+	require.Equal(t, []client.ScheduleBackfill{
+		{Start: scheduletest.Date("2023-01-01T00:59:59.999Z"), End: scheduletest.Date("2023-01-01T02:00:00Z")},
+		{Start: scheduletest.Date("2023-01-01T04:59:59.999Z"), End: scheduletest.Date("2023-01-01T06:00:00Z"), Overlap: enums.SCHEDULE_OVERLAP_POLICY_ALLOW_ALL},
+	}, schedules.Backfills)
+
+	// Every backfilled window matches the times the Evaluator expects in it, and no others.
+	evaluator, err := schedule.NewEvaluator(*schedules.Schedules["hourly"].Schedule.Spec)
+	require.NoError(t, err)
+	for i, backfill := range schedules.Backfills {
+		times := evaluator.Between(backfill.Start, backfill.End.Add(time.Nanosecond))
+		require.Len(t, times, len(plan.Windows[i].Times))
+	}
+}
+
+func TestPlanWaitsForJitterAndSettleMargin(t *testing.T) {
+	tests := []struct {
+		name   string
+		jitter time.Duration
+		now    string
+		want   []time.Time
+	}{
+		{"just started", 0, "2023-01-01T03:00:05Z", scheduletest.Dates("2023-01-01T01:00:00Z", "2023-01-01T02:00:00Z")},
+		{"settled", 0, "2023-01-01T03:00:30Z", scheduletest.Dates("2023-01-01T01:00:00Z", "2023-01-01T02:00:00Z", "2023-01-01T03:00:00Z")},
+		{"within jitter", 30 * time.Minute, "2023-01-01T03:20:00Z", scheduletest.Dates("2023-01-01T01:00:00Z", "2023-01-01T02:00:00Z")},
+		{"after jitter", 30 * time.Minute, "2023-01-01T03:31:00Z", scheduletest.Dates("2023-01-01T01:00:00Z", "2023-01-01T02:00:00Z", "2023-01-01T03:00:00Z")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schedules := newSchedules(t, "hourly", client.ScheduleSpec{CronExpressions: []string{"0 * * * *"}, Jitter: test.jitter})
+			planner := &Planner{
+				Schedules:  schedules,
+				Workflows:  &fakeVisibility{startTimes: scheduletest.Dates("2023-01-01T00:00:00Z")},
+				Namespaces: thirtyDays,
+				Namespace:  "default",
+				Now:        func() time.Time { return scheduletest.Date(test.now) },
+			}
+			plan, err := planner.Plan(context.Background(), "hourly", scheduletest.Date("2023-01-01T00:00:00Z"), scheduletest.Date("2023-01-02T00:00:00Z"))
+			require.NoError(t, err)
+			require.Equal(t, test.want, plan.Missing)
+		})
+	}
+}
+
+func TestApplyStopsAtFailure(t *testing.T) {
+	schedules := newSchedules(t, "hourly", client.ScheduleSpec{})
+	schedules.Fail = func(string) error {
+		if len(schedules.Backfills) == 1 {
+			return errors.New("backfill failed")
+		}
+		return nil
+	}
+	planner := &Planner{Schedules: schedules}
+	plan := &Plan{ScheduleID: "hourly", Windows: []Window{
+		{Times: scheduletest.Dates("2023-01-01T01:00:00Z")},
+		{Times: scheduletest.Dates("2023-01-01T03:00:00Z")},
+		{Times: scheduletest.Dates("2023-01-01T05:00:00Z")},
+	}}
+	err := planner.Apply(context.Background(), plan)
+	require.ErrorContains(t, err, "window 2")
+	require.Len(t, schedules.Backfills, 1)
+}
+
+func TestPlanWithoutGaps(t *testing.T) {
+	schedules := newSchedules(t, "daily", client.ScheduleSpec{CronExpressions: []string{"0 0 * * *"}})
+	workflows := &fakeVisibility{startTimes: scheduletest.Dates("2023-01-01T00:00:00Z", "2023-01-02T00:00:00Z")}
+	planner := &Planner{
+		Schedules:  schedules,
+		Workflows:  workflows,
+		Namespaces: thirtyDays,
+		Namespace:  "default",
+		Now:        func() time.Time { return scheduletest.Date("2023-01-03T00:00:30Z") },
+	}
+	plan, err := planner.Plan(context.Background(), "daily", scheduletest.Date("2023-01-01T00:00:00Z"), scheduletest.Date("2023-01-03T00:00:00Z"))
+	require.NoError(t, err)
+	require.Len(t, plan.Expected, 2)
+	require.Empty(t, plan.Missing)
+	require.Empty(t, plan.Windows)
+}
+
+func TestPlanStartsWhenTheScheduleWasCreated(t *testing.T) {
+	schedules := newSchedules(t, "hourly", client.ScheduleSpec{CronExpressions: []string{"0 * * * *"}})
+	schedules.Schedules["hourly"].Info.CreatedAt = scheduletest.Date("2023-01-01T02:30:00Z")
+	workflows := &fakeVisibility{startTimes: scheduletest.Dates("2023-01-01T04:00:00Z")}
+	planner := &Planner{
+		Schedules:  schedules,
+		Workflows:  workflows,
+		Namespaces: thirtyDays,
+		Namespace:  "default",
+		Now:        func() time.Time { return scheduletest.Date("2023-01-01T06:30:00Z") },
+	}
+
+	// The Schedule took no actions before it was created, so those are not missed.
+	plan, err := planner.Plan(context.Background(), "hourly", scheduletest.Date("2023-01-01T00:00:00Z"), scheduletest.Date("2023-01-01T06:00:00Z"))
+	require.NoError(t, err)
+	require.Equal(t, scheduletest.Date("2023-01-01T02:30:00Z"), plan.Start)
+	require.Equal(t, scheduletest.Dates("2023-01-01T03:00:00Z", "2023-01-01T04:00:00Z", "2023-01-01T05:00:00Z"), plan.Expected)
+	require.Equal(t, scheduletest.Dates("2023-01-01T03:00:00Z", "2023-01-01T05:00:00Z"), plan.Missing)
+	require.Contains(t, workflows.queries[0], `TemporalScheduledStartTime >= "2023-01-01T02:30:00Z"`)
+}
+
+func TestPlanRefusesRangesBeyondRetention(t *testing.T) {
+	schedules := newSchedules(t, "hourly", client.ScheduleSpec{CronExpressions: []string{"0 * * * *"}})
+	namespaces := &fakeNamespaces{retention: 24 * time.Hour}
+	planner := &Planner{
+		Schedules:  schedules,
+		Workflows:  &fakeVisibility{},
+		Namespaces: namespaces,
+		Namespace:  "default",
+		Now:        func() time.Time { return scheduletest.Date("2023-01-03T00:00:00Z") },
+	}
+
+	_, err := planner.Plan(context.Background(), "hourly", scheduletest.Date("2023-01-01T00:00:00Z"), scheduletest.Date("2023-01-02T00:00:00Z"))
+	require.ErrorIs(t, err, ErrBeyondRetention)
+	require.Equal(t, []string{"default"}, namespaces.described)
+
+	// Within the retention period.
+	_, err = planner.Plan(context.Background(), "hourly", scheduletest.Date("2023-01-02T01:00:00Z"), scheduletest.Date("2023-01-02T03:00:00Z"))
+	require.NoError(t, err)
+
+	// Overridden, without describing the Namespace.
+	planner.Namespaces = nil
+	planner.BeyondRetention = true
+	plan, err := planner.Plan(context.Background(), "hourly", scheduletest.Date("2023-01-01T00:00:00Z"), scheduletest.Date("2023-01-02T00:00:00Z"))
+	require.NoError(t, err)
+	require.Len(t, plan.Missing, 24)
+}
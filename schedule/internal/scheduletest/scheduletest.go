@@ -0,0 +1,301 @@
+// Package scheduletest provides an in-memory client.ScheduleClient for the tests of the schedule packages.
+package scheduletest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/temporalio/documentation-samples-go/schedule"
+)
+
+// ScheduleClient is an in-memory client.ScheduleClient. Like the Temporal Service, it stores specs in canonical form,
+// Workflow arguments, memos and Search Attributes as Payloads, and Workflows by type name.
+type ScheduleClient struct {
+	DataConverter converter.DataConverter
+	// Schedules are the Schedules by Id. Tests may change them directly.
+	Schedules map[string]*client.ScheduleDescription
+	// Calls records the calls that change Schedules, as "create id", "update id", "pause id", "unpause id",
+	// "backfill id" and "delete id". Updates skipped with temporal.ErrSkipScheduleUpdate are not recorded.
+	Calls []string
+	// Backfills records the backfills of every Schedule, in order.
+	Backfills []client.ScheduleBackfill
+	// Fail, if set, is called with each call before it changes anything, and the call fails with its error.
+	Fail func(call string) error
+	// Now returns the time that Create records as the CreatedAt of a Schedule. It defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewScheduleClient returns a ScheduleClient without Schedules that uses the default Data Converter.
+func NewScheduleClient() *ScheduleClient {
+	return &ScheduleClient{DataConverter: converter.GetDefaultDataConverter(), Schedules: map[string]*client.ScheduleDescription{}}
+}
+
+// NewOf allocates a T. It creates the Policy and State of a client.Schedule, whose types are not exported.
+func NewOf[T any](*T) *T {
+	return new(T)
+}
+
+// Date parses an RFC 3339 time, and panics when it is invalid.
+func Date(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Dates parses RFC 3339 times with Date.
+func Dates(s ...string) []time.Time {
+	var out []time.Time
+	for _, d := range s {
+		out = append(out, Date(d))
+	}
+	return out
+}
+
+func (c *ScheduleClient) call(name, id string) error {
+	if c.Fail != nil {
+		if err := c.Fail(name + " " + id); err != nil {
+			return err
+		}
+	}
+	c.Calls = append(c.Calls, name+" "+id)
+	return nil
+}
+
+// Create implements client.ScheduleClient.
+func (c *ScheduleClient) Create(_ context.Context, options client.ScheduleOptions) (client.ScheduleHandle, error) {
+	if _, ok := c.Schedules[options.ID]; ok {
+		return nil, temporal.ErrScheduleAlreadyRunning
+	}
+	s := client.Schedule{Spec: &options.Spec, Action: options.Action}
+	s.Policy = NewOf(s.Policy)
+	s.Policy.Overlap = options.Overlap
+	s.Policy.CatchupWindow = options.CatchupWindow
+	s.Policy.PauseOnFailure = options.PauseOnFailure
+	s.State = NewOf(s.State)
+	s.State.Note = options.Note
+	s.State.Paused = options.Paused
+	s.State.LimitedActions = options.RemainingActions > 0
+	s.State.RemainingActions = options.RemainingActions
+	memo, err := encode(c.DataConverter, options.Memo)
+	if err != nil {
+		return nil, err
+	}
+	// Search Attributes are not encoded by custom Data Converters.
+	searchAttributes, err := encode(converter.GetDefaultDataConverter(), options.SearchAttributes)
+	if err != nil {
+		return nil, err
+	}
+	desc := &client.ScheduleDescription{
+		Memo:             &commonpb.Memo{Fields: memo},
+		SearchAttributes: &commonpb.SearchAttributes{IndexedFields: searchAttributes},
+	}
+	desc.Info.CreatedAt = time.Now()
+	if c.Now != nil {
+		desc.Info.CreatedAt = c.Now()
+	}
+	if desc.Schedule, err = c.store(s); err != nil {
+		return nil, err
+	}
+	if err := c.call("create", options.ID); err != nil {
+		return nil, err
+	}
+	c.Schedules[options.ID] = desc
+	return &handle{c, options.ID}, nil
+}
+
+func encode(dc converter.DataConverter, values map[string]interface{}) (map[string]*commonpb.Payload, error) {
+	fields := map[string]*commonpb.Payload{}
+	for key, value := range values {
+		payload, err := dc.ToPayload(value)
+		if err != nil {
+			return nil, err
+		}
+		fields[key] = payload
+	}
+	return fields, nil
+}
+
+// store returns s as the Temporal Service stores it.
+func (c *ScheduleClient) store(s client.Schedule) (client.Schedule, error) {
+	spec, err := schedule.CanonicalSpec(*s.Spec)
+	if err != nil {
+		return client.Schedule{}, err
+	}
+	action := *s.Action.(*client.ScheduleWorkflowAction)
+	action.Workflow = fmt.Sprint(action.Workflow)
+	var args []interface{}
+	for _, arg := range action.Args {
+		if _, ok := arg.(*commonpb.Payload); !ok {
+			if arg, err = c.DataConverter.ToPayload(arg); err != nil {
+				return client.Schedule{}, err
+			}
+		}
+		args = append(args, arg)
+	}
+	action.Args = args
+	policy, state := *s.Policy, *s.State
+	return client.Schedule{Spec: &spec, Action: &action, Policy: &policy, State: &state}, nil
+}
+
+// List implements client.ScheduleClient. It lists the Schedules sorted by Id.
+func (c *ScheduleClient) List(context.Context, client.ScheduleListOptions) (client.ScheduleListIterator, error) {
+	var entries []*client.ScheduleListEntry
+	for id, desc := range c.Schedules {
+		entries = append(entries, &client.ScheduleListEntry{
+			ID:               id,
+			Spec:             desc.Schedule.Spec,
+			Note:             desc.Schedule.State.Note,
+			Paused:           desc.Schedule.State.Paused,
+			WorkflowType:     workflow.Type{Name: fmt.Sprint(desc.Schedule.Action.(*client.ScheduleWorkflowAction).Workflow)},
+			Memo:             desc.Memo,
+			SearchAttributes: desc.SearchAttributes,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	return &iterator{entries: entries}, nil
+}
+
+// GetHandle implements client.ScheduleClient.
+func (c *ScheduleClient) GetHandle(_ context.Context, id string) client.ScheduleHandle {
+	return &handle{c, id}
+}
+
+type iterator struct {
+	entries []*client.ScheduleListEntry
+}
+
+func (i *iterator) HasNext() bool {
+	return len(i.entries) > 0
+}
+
+func (i *iterator) Next() (*client.ScheduleListEntry, error) {
+	entry := i.entries[0]
+	i.entries = i.entries[1:]
+	return entry, nil
+}
+
+type handle struct {
+	c  *ScheduleClient
+	id string
+}
+
+func (h *handle) GetID() string {
+	return h.id
+}
+
+func (h *handle) get() (*client.ScheduleDescription, error) {
+	desc, ok := h.c.Schedules[h.id]
+	if !ok {
+		return nil, serviceerror.NewNotFound(fmt.Sprintf("schedule %s not found", h.id))
+	}
+	return desc, nil
+}
+
+func (h *handle) Describe(context.Context) (*client.ScheduleDescription, error) {
+	desc, err := h.get()
+	if err != nil {
+		return nil, err
+	}
+	// Copy the parts that DoUpdate may change.
+	s := desc.Schedule
+	spec, action, policy, state := *s.Spec, *s.Action.(*client.ScheduleWorkflowAction), *s.Policy, *s.State
+	return &client.ScheduleDescription{
+		Schedule:         client.Schedule{Spec: &spec, Action: &action, Policy: &policy, State: &state},
+		Info:             desc.Info,
+		Memo:             desc.Memo,
+		SearchAttributes: desc.SearchAttributes,
+	}, nil
+}
+
+func (h *handle) Update(ctx context.Context, options client.ScheduleUpdateOptions) error {
+	desc, err := h.Describe(ctx)
+	if err != nil {
+		return err
+	}
+	update, err := options.DoUpdate(client.ScheduleUpdateInput{Description: *desc})
+	if errors.Is(err, temporal.ErrSkipScheduleUpdate) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	s, err := h.c.store(*update.Schedule)
+	if err != nil {
+		return err
+	}
+	if err := h.c.call("update", h.id); err != nil {
+		return err
+	}
+	h.c.Schedules[h.id].Schedule = s
+	return nil
+}
+
+func (h *handle) Delete(context.Context) error {
+	if _, err := h.get(); err != nil {
+		return err
+	}
+	if err := h.c.call("delete", h.id); err != nil {
+		return err
+	}
+	delete(h.c.Schedules, h.id)
+	return nil
+}
+
+func (h *handle) Backfill(_ context.Context, options client.ScheduleBackfillOptions) error {
+	if _, err := h.get(); err != nil {
+		return err
+	}
+	if err := h.c.call("backfill", h.id); err != nil {
+		return err
+	}
+	h.c.Backfills = append(h.c.Backfills, options.Backfill...)
+	return nil
+}
+
+func (h *handle) Trigger(context.Context, client.ScheduleTriggerOptions) error {
+	return errors.New("not implemented")
+}
+
+func (h *handle) Pause(_ context.Context, options client.SchedulePauseOptions) error {
+	desc, err := h.get()
+	if err != nil {
+		return err
+	}
+	if err := h.c.call("pause", h.id); err != nil {
+		return err
+	}
+	desc.Schedule.State.Paused = true
+	desc.Schedule.State.Note = options.Note
+	if options.Note == "" {
+		desc.Schedule.State.Note = "Paused via Go SDK"
+	}
+	return nil
+}
+
+func (h *handle) Unpause(_ context.Context, options client.ScheduleUnpauseOptions) error {
+	desc, err := h.get()
+	if err != nil {
+		return err
+	}
+	if err := h.c.call("unpause", h.id); err != nil {
+		return err
+	}
+	desc.Schedule.State.Paused = false
+	desc.Schedule.State.Note = options.Note
+	if options.Note == "" {
+		desc.Schedule.State.Note = "Unpaused via Go SDK"
+	}
+	return nil
+}
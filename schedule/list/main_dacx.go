@@ -7,12 +7,20 @@ import (
 	"github.com/pborman/uuid"
 	"github.com/temporalio/documentation-samples-go/schedule"
 	"go.temporal.io/sdk/client"
+
+	"documentation-samples-go/codec"
 )
 
 func main() {
 	ctx := context.Background()
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		log.Fatalln("Unable to create Data Converter", err)
+	}
 	temporalClient, err := client.Dial(client.Options{
-		HostPort: client.DefaultHostPort,
+		HostPort:      client.DefaultHostPort,
+		DataConverter: dataConverter,
 	})
 	if err != nil {
 		log.Fatalln("Unable to create Temporal Client", err)
@@ -62,5 +70,5 @@ tags:
 - code sample
 - schedule
 - list
-lines: 12, 40-46, 49-53
+lines: 14, 48-54, 57-61
 @dacx */
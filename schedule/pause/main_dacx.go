@@ -7,12 +7,20 @@ import (
 	"time"
 
 	"go.temporal.io/sdk/client"
+
+	"documentation-samples-go/codec"
 )
 
 func main() {
 	ctx := context.Background()
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		log.Fatalln("Unable to create Data Converter", err)
+	}
 	temporalClient, err := client.Dial(client.Options{
-		HostPort: client.DefaultHostPort,
+		HostPort:      client.DefaultHostPort,
+		DataConverter: dataConverter,
 	})
 	if err != nil {
 		log.Fatalln("Unable to create Temporal Client", err)
@@ -69,5 +77,5 @@ tags:
 - schedule
 - pause
 - unpause
-lines: 12, 51-59, 30-32, 42-44
+lines: 14, 59-67, 38-40, 50-52
 @dacx */
@@ -0,0 +1,208 @@
+// Package reconcile keeps the Schedules of a Namespace in line with schedule files.
+//
+// A schedule file is YAML or JSON, and describes each Schedule in terms of client.ScheduleOptions:
+//
+//	schedules:
+//	  - id: daily-report
+//	    spec:
+//	      cron: ["0 9 * * MON-FRI"]
+//	      timeZone: Europe/Paris
+//	    action:
+//	      workflowType: ScheduleWorkflow
+//	      taskQueue: schedule
+//	      args: ["daily"]
+//	    policies:
+//	      overlap: buffer_one
+//	    state:
+//	      note: Sends the daily report
+//
+// A Reconciler compares the files with the Schedules of the Namespace, makes a Plan, and applies it.
+package reconcile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"go.temporal.io/sdk/client"
+	"gopkg.in/yaml.v3"
+
+	"github.com/temporalio/documentation-samples-go/schedule"
+)
+
+// File is the content of a schedule file.
+type File struct {
+	Schedules []Definition `yaml:"schedules"`
+}
+
+// Definition describes a Schedule. Only Workflow Actions are supported.
+type Definition struct {
+	ID       string   `yaml:"id"`
+	Spec     Spec     `yaml:"spec"`
+	Action   Action   `yaml:"action"`
+	Policies Policies `yaml:"policies"`
+	State    State    `yaml:"state"`
+}
+
+// Spec is client.ScheduleSpec, with intervals and calendars written as schedule.ParseInterval and schedule.ParseCalendar accept them.
+type Spec struct {
+	Cron      []string      `yaml:"cron"`
+	Intervals []string      `yaml:"intervals"`
+	Calendars []string      `yaml:"calendars"`
+	Skip      []string      `yaml:"skip"`
+	StartAt   time.Time     `yaml:"startAt"`
+	EndAt     time.Time     `yaml:"endAt"`
+	Jitter    time.Duration `yaml:"jitter"`
+	TimeZone  string        `yaml:"timeZone"`
+}
+
+// Action is client.ScheduleWorkflowAction. WorkflowID defaults to the Schedule Id with a _workflow suffix.
+type Action struct {
+	WorkflowType     string        `yaml:"workflowType"`
+	WorkflowID       string        `yaml:"workflowId"`
+	TaskQueue        string        `yaml:"taskQueue"`
+	Args             []interface{} `yaml:"args"`
+	ExecutionTimeout time.Duration `yaml:"executionTimeout"`
+	RunTimeout       time.Duration `yaml:"runTimeout"`
+	TaskTimeout      time.Duration `yaml:"taskTimeout"`
+}
+
+// Policies are the Overlap, CatchupWindow and PauseOnFailure fields of client.ScheduleOptions.
+// Overlap is written as schedule.ParseOverlapPolicy accepts it.
+type Policies struct {
+	Overlap        string        `yaml:"overlap"`
+	CatchupWindow  time.Duration `yaml:"catchupWindow"`
+	PauseOnFailure bool          `yaml:"pauseOnFailure"`
+}
+
+// State is the Note, Paused and RemainingActions fields of client.ScheduleOptions.
+// RemainingActions counts down as the Schedule runs, so it is only used when the Schedule is created.
+type State struct {
+	Note             string `yaml:"note"`
+	Paused           bool   `yaml:"paused"`
+	RemainingActions int    `yaml:"remainingActions"`
+}
+
+// Load reads the schedule files at paths and returns their Definitions, which must be valid and have distinct Ids.
+func Load(paths ...string) ([]Definition, error) {
+	var defs []Definition
+	files := map[string]string{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		file, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for _, def := range file.Schedules {
+			if other, ok := files[def.ID]; ok {
+				return nil, fmt.Errorf("%s: schedule %s is already defined in %s", path, def.ID, other)
+			}
+			files[def.ID] = path
+			defs = append(defs, def)
+		}
+	}
+	return defs, nil
+}
+
+// Parse decodes and validates a schedule file. Unknown fields are errors, so that typos are not ignored.
+func Parse(data []byte) (*File, error) {
+	var file File
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil {
+		return nil, err
+	}
+	ids := map[string]bool{}
+	for _, def := range file.Schedules {
+		if _, err := def.Options(); err != nil {
+			return nil, err
+		}
+		if ids[def.ID] {
+			return nil, fmt.Errorf("schedule %s is defined more than once", def.ID)
+		}
+		ids[def.ID] = true
+	}
+	return &file, nil
+}
+
+// Options returns the options that create the Schedule.
+func (d Definition) Options() (client.ScheduleOptions, error) {
+	if d.ID == "" {
+		return client.ScheduleOptions{}, errors.New("schedule without an id")
+	}
+	if d.Action.WorkflowType == "" || d.Action.TaskQueue == "" {
+		return client.ScheduleOptions{}, fmt.Errorf("schedule %s: action needs a workflowType and a taskQueue", d.ID)
+	}
+	spec, err := d.Spec.scheduleSpec()
+	if err != nil {
+		return client.ScheduleOptions{}, fmt.Errorf("schedule %s: %w", d.ID, err)
+	}
+	overlap, err := schedule.ParseOverlapPolicy(d.Policies.Overlap)
+	if err != nil {
+		return client.ScheduleOptions{}, fmt.Errorf("schedule %s: %w", d.ID, err)
+	}
+	workflowID := d.Action.WorkflowID
+	if workflowID == "" {
+		workflowID = d.ID + "_workflow"
+	}
+	return client.ScheduleOptions{
+		ID:   d.ID,
+		Spec: spec,
+		Action: &client.ScheduleWorkflowAction{
+			ID:                       workflowID,
+			Workflow:                 d.Action.WorkflowType,
+			Args:                     d.Action.Args,
+			TaskQueue:                d.Action.TaskQueue,
+			WorkflowExecutionTimeout: d.Action.ExecutionTimeout,
+			WorkflowRunTimeout:       d.Action.RunTimeout,
+			WorkflowTaskTimeout:      d.Action.TaskTimeout,
+		},
+		Overlap:          overlap,
+		CatchupWindow:    d.Policies.CatchupWindow,
+		PauseOnFailure:   d.Policies.PauseOnFailure,
+		Note:             d.State.Note,
+		Paused:           d.State.Paused,
+		RemainingActions: d.State.RemainingActions,
+	}, nil
+}
+
+func (s Spec) scheduleSpec() (client.ScheduleSpec, error) {
+	spec := client.ScheduleSpec{
+		CronExpressions: s.Cron,
+		StartAt:         s.StartAt,
+		EndAt:           s.EndAt,
+		Jitter:          s.Jitter,
+		TimeZoneName:    s.TimeZone,
+	}
+	for _, interval := range s.Intervals {
+		parsed, err := schedule.ParseInterval(interval)
+		if err != nil {
+			return client.ScheduleSpec{}, err
+		}
+		spec.Intervals = append(spec.Intervals, parsed)
+	}
+	for _, calendar := range s.Calendars {
+		parsed, err := schedule.ParseCalendar(calendar)
+		if err != nil {
+			return client.ScheduleSpec{}, err
+		}
+		spec.Calendars = append(spec.Calendars, parsed)
+	}
+	for _, skip := range s.Skip {
+		parsed, err := schedule.ParseCalendar(skip)
+		if err != nil {
+			return client.ScheduleSpec{}, err
+		}
+		spec.Skip = append(spec.Skip, parsed)
+	}
+	// Catch invalid cron expressions before the Temporal Service does.
+	if _, err := schedule.CanonicalSpec(spec); err != nil {
+		return client.ScheduleSpec{}, err
+	}
+	return spec, nil
+}
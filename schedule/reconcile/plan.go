@@ -0,0 +1,360 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+
+	"github.com/temporalio/documentation-samples-go/schedule"
+)
+
+const (
+	// ManagedMemoKey is the Memo key that marks the Schedules created by a Reconciler.
+	// Only those Schedules are pruned.
+	ManagedMemoKey = "managedBy"
+	// ManagedMemoValue is the value of ManagedMemoKey.
+	ManagedMemoValue = "schedule-files"
+)
+
+// defaultCatchupWindow is the catchup window the Temporal Service uses when none is set.
+const defaultCatchupWindow = 365 * 24 * time.Hour
+
+// ChangeKind is what a Change does to a Schedule.
+type ChangeKind string
+
+const (
+	Create ChangeKind = "create"
+	Update ChangeKind = "update"
+	Delete ChangeKind = "delete"
+)
+
+// Diff is a field of a Schedule that a Change sets. Old is empty for created Schedules and New for deleted ones.
+type Diff struct {
+	Field string `json:"field"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+// Change is a Schedule to create, update or delete.
+type Change struct {
+	Kind  ChangeKind `json:"kind"`
+	ID    string     `json:"id"`
+	Diffs []Diff     `json:"diffs,omitempty"`
+
+	options client.ScheduleOptions
+}
+
+// Plan is the list of Changes that make the Schedules of a Namespace match their Definitions.
+type Plan struct {
+	Changes []Change `json:"changes"`
+}
+
+// Write prints the plan: + for Schedules to create, ~ for Schedules to update and - for Schedules to delete.
+func (p *Plan) Write(w io.Writer) error {
+	if len(p.Changes) == 0 {
+		_, err := fmt.Fprintln(w, "No changes. The schedules match the files.")
+		return err
+	}
+	counts := map[ChangeKind]int{}
+	for _, change := range p.Changes {
+		counts[change.Kind]++
+		sign := map[ChangeKind]string{Create: "+", Update: "~", Delete: "-"}[change.Kind]
+		if _, err := fmt.Fprintf(w, "%s %s %s\n", sign, change.Kind, change.ID); err != nil {
+			return err
+		}
+		for _, diff := range change.Diffs {
+			var err error
+			switch change.Kind {
+			case Create:
+				_, err = fmt.Fprintf(w, "    %s: %s\n", diff.Field, diff.New)
+			default:
+				_, err = fmt.Fprintf(w, "    %s: %s -> %s\n", diff.Field, diff.Old, diff.New)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintf(w, "Plan: %d to create, %d to update, %d to delete.\n", counts[Create], counts[Update], counts[Delete])
+	return err
+}
+
+// Reconciler compares Definitions with the Schedules of a Namespace.
+type Reconciler struct {
+	Schedules client.ScheduleClient
+	// DataConverter decodes the Workflow arguments and Memos of existing Schedules.
+	// It must be the Data Converter of the client of Schedules.
+	DataConverter converter.DataConverter
+	// Prune deletes the Schedules that were created by a Reconciler and are no longer defined.
+	Prune bool
+}
+
+// Plan returns the Changes that make the Schedules match defs.
+// Changes are ordered by Schedule Id, creations and updates first, then deletions.
+func (r *Reconciler) Plan(ctx context.Context, defs []Definition) (*Plan, error) {
+	existing := map[string]*client.ScheduleListEntry{}
+	iter, err := r.Schedules.List(ctx, client.ScheduleListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list schedules: %w", err)
+	}
+	for iter.HasNext() {
+		entry, err := iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("unable to list schedules: %w", err)
+		}
+		existing[entry.ID] = entry
+	}
+
+	sorted := append([]Definition(nil), defs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	plan := &Plan{Changes: []Change{}}
+	defined := map[string]bool{}
+	for _, def := range sorted {
+		defined[def.ID] = true
+		options, err := def.Options()
+		if err != nil {
+			return nil, err
+		}
+		want, err := optionFields(options)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := existing[def.ID]; !ok {
+			diffs := diffFields(defaultFields, want)
+			for i := range diffs {
+				diffs[i].Old = ""
+			}
+			plan.Changes = append(plan.Changes, Change{Kind: Create, ID: def.ID, Diffs: diffs, options: options})
+			continue
+		}
+		desc, err := r.Schedules.GetHandle(ctx, def.ID).Describe(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to describe schedule %s: %w", def.ID, err)
+		}
+		have, err := r.describedFields(desc)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %s: %w", def.ID, err)
+		}
+		if diffs := diffFields(have, want); len(diffs) > 0 {
+			plan.Changes = append(plan.Changes, Change{Kind: Update, ID: def.ID, Diffs: diffs, options: options})
+		}
+	}
+
+	if r.Prune {
+		var ids []string
+		for id, entry := range existing {
+			if defined[id] {
+				continue
+			}
+			managed, err := r.managed(entry.Memo)
+			if err != nil {
+				return nil, fmt.Errorf("schedule %s: %w", id, err)
+			}
+			if managed {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			plan.Changes = append(plan.Changes, Change{Kind: Delete, ID: id})
+		}
+	}
+	return plan, nil
+}
+
+// Apply makes the Changes of plan in order, and stops at the first one that fails.
+// Changes made before the failure are kept; run Plan again to see what is left.
+func (r *Reconciler) Apply(ctx context.Context, plan *Plan) error {
+	for _, change := range plan.Changes {
+		var err error
+		switch change.Kind {
+		case Create:
+			options := change.options
+			options.Memo = map[string]interface{}{ManagedMemoKey: ManagedMemoValue}
+			_, err = r.Schedules.Create(ctx, options)
+		case Update:
+			err = r.Schedules.GetHandle(ctx, change.ID).Update(ctx, client.ScheduleUpdateOptions{
+				DoUpdate: func(input client.ScheduleUpdateInput) (*client.ScheduleUpdate, error) {
+					return &client.ScheduleUpdate{Schedule: updatedSchedule(input.Description.Schedule, change.options)}, nil
+				},
+			})
+		case Delete:
+			err = r.Schedules.GetHandle(ctx, change.ID).Delete(ctx)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to %s schedule %s: %w", change.Kind, change.ID, err)
+		}
+	}
+	return nil
+}
+
+// updatedSchedule returns s with the fields that a Definition sets replaced by those of options.
+// Describe always sets the Policy and State of s.
+func updatedSchedule(s client.Schedule, options client.ScheduleOptions) *client.Schedule {
+	spec := options.Spec
+	s.Spec = &spec
+	s.Action = options.Action
+	s.Policy.Overlap = options.Overlap
+	s.Policy.CatchupWindow = options.CatchupWindow
+	s.Policy.PauseOnFailure = options.PauseOnFailure
+	s.State.Note = options.Note
+	s.State.Paused = options.Paused
+	return &s
+}
+
+func (r *Reconciler) managed(memo *commonpb.Memo) (bool, error) {
+	payload, ok := memo.GetFields()[ManagedMemoKey]
+	if !ok {
+		return false, nil
+	}
+	var value string
+	if err := r.dataConverter().FromPayload(payload, &value); err != nil {
+		return false, fmt.Errorf("unable to decode memo %s: %w", ManagedMemoKey, err)
+	}
+	return value == ManagedMemoValue, nil
+}
+
+func (r *Reconciler) dataConverter() converter.DataConverter {
+	if r.DataConverter == nil {
+		return converter.GetDefaultDataConverter()
+	}
+	return r.DataConverter
+}
+
+// field is a field of a Schedule, written so that the same value compares equal
+// whether it comes from a Definition or from Describe.
+type field struct {
+	name, value string
+}
+
+func optionFields(options client.ScheduleOptions) ([]field, error) {
+	action := options.Action.(*client.ScheduleWorkflowAction)
+	return scheduleFields(options.Spec, action, action.Args, options.Overlap, options.CatchupWindow, options.PauseOnFailure, options.Note, options.Paused)
+}
+
+func (r *Reconciler) describedFields(desc *client.ScheduleDescription) ([]field, error) {
+	s := desc.Schedule
+	action, ok := s.Action.(*client.ScheduleWorkflowAction)
+	if !ok {
+		return nil, fmt.Errorf("action is not a workflow")
+	}
+	var args []interface{}
+	for _, arg := range action.Args {
+		if payload, ok := arg.(*commonpb.Payload); ok {
+			var value interface{}
+			if err := r.dataConverter().FromPayload(payload, &value); err != nil {
+				return nil, fmt.Errorf("unable to decode workflow arguments: %w", err)
+			}
+			arg = value
+		}
+		args = append(args, arg)
+	}
+	var spec client.ScheduleSpec
+	if s.Spec != nil {
+		spec = *s.Spec
+	}
+	return scheduleFields(spec, action, args, s.Policy.Overlap, s.Policy.CatchupWindow, s.Policy.PauseOnFailure, s.State.Note, s.State.Paused)
+}
+
+func scheduleFields(
+	spec client.ScheduleSpec,
+	action *client.ScheduleWorkflowAction,
+	args []interface{},
+	overlap enums.ScheduleOverlapPolicy,
+	catchupWindow time.Duration,
+	pauseOnFailure bool,
+	note string,
+	paused bool,
+) ([]field, error) {
+	spec, err := schedule.CanonicalSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	var calendars, intervals, skip []string
+	for _, calendar := range spec.Calendars {
+		calendars = append(calendars, schedule.FormatCalendar(calendar))
+	}
+	for _, interval := range spec.Intervals {
+		intervals = append(intervals, schedule.FormatInterval(interval))
+	}
+	for _, calendar := range spec.Skip {
+		skip = append(skip, schedule.FormatCalendar(calendar))
+	}
+	if len(args) == 0 {
+		args = []interface{}{}
+	}
+	encodedArgs, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode workflow arguments: %w", err)
+	}
+	if overlap == enums.SCHEDULE_OVERLAP_POLICY_UNSPECIFIED {
+		overlap = enums.SCHEDULE_OVERLAP_POLICY_SKIP
+	}
+	if catchupWindow == 0 {
+		catchupWindow = defaultCatchupWindow
+	}
+	return []field{
+		{"spec.calendars", sortedList(calendars)},
+		{"spec.intervals", sortedList(intervals)},
+		{"spec.skip", sortedList(skip)},
+		{"spec.startAt", formatTime(spec.StartAt)},
+		{"spec.endAt", formatTime(spec.EndAt)},
+		{"spec.jitter", formatDuration(spec.Jitter)},
+		{"spec.timeZone", spec.TimeZoneName},
+		{"action.workflowType", fmt.Sprint(action.Workflow)},
+		{"action.workflowId", action.ID},
+		{"action.taskQueue", action.TaskQueue},
+		{"action.args", string(encodedArgs)},
+		{"action.executionTimeout", formatDuration(action.WorkflowExecutionTimeout)},
+		{"action.runTimeout", formatDuration(action.WorkflowRunTimeout)},
+		{"action.taskTimeout", formatDuration(action.WorkflowTaskTimeout)},
+		{"policies.overlap", schedule.FormatOverlapPolicy(overlap)},
+		{"policies.catchupWindow", formatDuration(catchupWindow)},
+		{"policies.pauseOnFailure", strconv.FormatBool(pauseOnFailure)},
+		{"state.note", note},
+		{"state.paused", strconv.FormatBool(paused)},
+	}, nil
+}
+
+// defaultFields are the fields of a Schedule created with empty options. The plan of a new Schedule shows the fields that differ.
+var defaultFields, _ = scheduleFields(client.ScheduleSpec{}, &client.ScheduleWorkflowAction{Workflow: ""}, nil, 0, 0, false, "", false)
+
+// diffFields returns the fields of want that differ from have.
+func diffFields(have, want []field) []Diff {
+	var diffs []Diff
+	for i, w := range want {
+		if have[i].value != w.value {
+			diffs = append(diffs, Diff{Field: w.name, Old: have[i].value, New: w.value})
+		}
+	}
+	return diffs
+}
+
+func sortedList(values []string) string {
+	sort.Strings(values)
+	return "[" + strings.Join(values, ", ") + "]"
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
@@ -0,0 +1,126 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/client"
+
+	"github.com/temporalio/documentation-samples-go/schedule/internal/scheduletest"
+)
+
+func TestLoad(t *testing.T) {
+	fromYAML, err := Load("testdata/schedules.yaml")
+	require.NoError(t, err)
+	require.Len(t, fromYAML, 2)
+	fromJSON, err := Load("testdata/schedules.json")
+	require.NoError(t, err)
+	require.Equal(t, fromYAML, fromJSON)
+
+	options, err := fromYAML[0].Options()
+	require.NoError(t, err)
+	require.Equal(t, "daily-report_workflow", options.Action.(*client.ScheduleWorkflowAction).ID)
+
+	_, err = Load("testdata/schedules.yaml", "testdata/schedules.json")
+	require.ErrorContains(t, err, "already defined")
+}
+
+func TestParseErrors(t *testing.T) {
+	action := "action: {workflowType: ScheduleWorkflow, taskQueue: schedule}"
+	tests := map[string]string{
+		"unknown field":   "schedules: [{id: a, " + action + ", sched: {}}]",
+		"missing id":      "schedules: [{" + action + "}]",
+		"missing action":  "schedules: [{id: a}]",
+		"duplicate id":    "schedules: [{id: a, " + action + "}, {id: a, " + action + "}]",
+		"invalid cron":    "schedules: [{id: a, " + action + ", spec: {cron: ['* * *']}}]",
+		"invalid overlap": "schedules: [{id: a, " + action + ", policies: {overlap: sometimes}}]",
+		"invalid jitter":  "schedules: [{id: a, " + action + ", spec: {jitter: soon}}]",
+	}
+	for name, file := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(file))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestPlanAndApply(t *testing.T) {
+	ctx := context.Background()
+	fake := scheduletest.NewScheduleClient()
+	r := &Reconciler{Schedules: fake}
+	defs, err := Load("testdata/schedules.yaml")
+	require.NoError(t, err)
+
+	plan, err := r.Plan(ctx, defs)
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 2)
+	require.Equal(t, Create, plan.Changes[0].Kind)
+	require.Equal(t, "cleanup", plan.Changes[0].ID)
+	require.Contains(t, plan.Changes[1].Diffs, Diff{Field: "state.note", New: "Sends the daily report"})
+	require.NoError(t, r.Apply(ctx, plan))
+	require.Equal(t, []string{"create cleanup", "create daily-report"}, fake.Calls)
+	require.Equal(t, 10, fake.Schedules["cleanup"].Schedule.State.RemainingActions)
+
+	// The Schedules were stored in canonical form, and still match the files.
+	plan, err = r.Plan(ctx, defs)
+	require.NoError(t, err)
+	require.Empty(t, plan.Changes)
+	var out bytes.Buffer
+	require.NoError(t, plan.Write(&out))
+	require.Equal(t, "No changes. The schedules match the files.\n", out.String())
+
+	// Only the changed fields are in the plan, and the fields a file does not set are kept.
+	defs[0].Spec.Cron = []string{"0 10 * * MON-FRI"}
+	defs[0].State.Note = "Sends the daily report later"
+	plan, err = r.Plan(ctx, defs)
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	change := plan.Changes[0]
+	require.Equal(t, Update, change.Kind)
+	require.Equal(t, "daily-report", change.ID)
+	require.Len(t, change.Diffs, 2)
+	require.Equal(t, "spec.calendars", change.Diffs[0].Field)
+	require.Equal(t, Diff{Field: "state.note", Old: "Sends the daily report", New: "Sends the daily report later"}, change.Diffs[1])
+	require.NoError(t, r.Apply(ctx, plan))
+
+	plan, err = r.Plan(ctx, defs)
+	require.NoError(t, err)
+	require.Empty(t, plan.Changes)
+	require.Equal(t, 10, fake.Schedules["cleanup"].Schedule.State.RemainingActions)
+}
+
+func TestPrune(t *testing.T) {
+	ctx := context.Background()
+	fake := scheduletest.NewScheduleClient()
+	r := &Reconciler{Schedules: fake}
+	defs, err := Load("testdata/schedules.yaml")
+	require.NoError(t, err)
+	plan, err := r.Plan(ctx, defs)
+	require.NoError(t, err)
+	require.NoError(t, r.Apply(ctx, plan))
+	// A Schedule created by hand is never pruned.
+	_, err = fake.Create(ctx, client.ScheduleOptions{
+		ID:     "manual",
+		Action: &client.ScheduleWorkflowAction{Workflow: "ScheduleWorkflow", TaskQueue: "schedule"},
+	})
+	require.NoError(t, err)
+
+	defs = defs[:1]
+	plan, err = r.Plan(ctx, defs)
+	require.NoError(t, err)
+	require.Empty(t, plan.Changes)
+
+	r.Prune = true
+	plan, err = r.Plan(ctx, defs)
+	require.NoError(t, err)
+	require.Equal(t, []Change{{Kind: Delete, ID: "cleanup"}}, plan.Changes)
+	var out bytes.Buffer
+	require.NoError(t, plan.Write(&out))
+	require.Equal(t, "- delete cleanup\nPlan: 0 to create, 0 to update, 1 to delete.\n", out.String())
+
+	require.NoError(t, r.Apply(ctx, plan))
+	require.Contains(t, fake.Schedules, "manual")
+	require.NotContains(t, fake.Schedules, "cleanup")
+}
@@ -0,0 +1,311 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/client"
+)
+
+/*
+Schedule Specs and policies can be written as short strings, for command-line flags and schedule files:
+
+- An overlap policy is one of skip, buffer_one, buffer_all, cancel_other, terminate_other or allow_all.
+- An interval is a duration, optionally followed by an offset: "1h" or "1h/15m".
+- A calendar is a list of field=ranges pairs separated by semicolons: "hour=9;minute=30;day_of_week=1-5".
+  The fields are second, minute, hour, day_of_month, month, year and day_of_week (0 is Sunday).
+  Ranges are separated by commas, and each one is "*", "n", "n-m" or "n-m/step".
+  Fields that are left out take the defaults of client.ScheduleCalendarSpec: zero for second, minute and hour, and every value for the others.
+*/
+
+var overlapPolicies = map[string]enums.ScheduleOverlapPolicy{
+	"skip":            enums.SCHEDULE_OVERLAP_POLICY_SKIP,
+	"buffer_one":      enums.SCHEDULE_OVERLAP_POLICY_BUFFER_ONE,
+	"buffer_all":      enums.SCHEDULE_OVERLAP_POLICY_BUFFER_ALL,
+	"cancel_other":    enums.SCHEDULE_OVERLAP_POLICY_CANCEL_OTHER,
+	"terminate_other": enums.SCHEDULE_OVERLAP_POLICY_TERMINATE_OTHER,
+	"allow_all":       enums.SCHEDULE_OVERLAP_POLICY_ALLOW_ALL,
+}
+
+// ParseOverlapPolicy returns the overlap policy called name.
+// An empty name returns the unspecified policy, which the Temporal Service treats as skip.
+func ParseOverlapPolicy(name string) (enums.ScheduleOverlapPolicy, error) {
+	if name == "" {
+		return enums.SCHEDULE_OVERLAP_POLICY_UNSPECIFIED, nil
+	}
+	policy, ok := overlapPolicies[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown overlap policy %q", name)
+	}
+	return policy, nil
+}
+
+// FormatOverlapPolicy returns the name of policy, as accepted by ParseOverlapPolicy.
+func FormatOverlapPolicy(policy enums.ScheduleOverlapPolicy) string {
+	for name, p := range overlapPolicies {
+		if p == policy {
+			return name
+		}
+	}
+	return ""
+}
+
+// ParseInterval parses an interval written as "every" or "every/offset".
+func ParseInterval(s string) (client.ScheduleIntervalSpec, error) {
+	every, offset, hasOffset := strings.Cut(s, "/")
+	var spec client.ScheduleIntervalSpec
+	var err error
+	spec.Every, err = time.ParseDuration(every)
+	if err != nil {
+		return client.ScheduleIntervalSpec{}, fmt.Errorf("invalid interval %q: %w", s, err)
+	}
+	if spec.Every <= 0 {
+		return client.ScheduleIntervalSpec{}, fmt.Errorf("invalid interval %q: must be positive", s)
+	}
+	if hasOffset {
+		spec.Offset, err = time.ParseDuration(offset)
+		if err != nil {
+			return client.ScheduleIntervalSpec{}, fmt.Errorf("invalid interval offset %q: %w", s, err)
+		}
+		if spec.Offset < 0 || spec.Offset >= spec.Every {
+			return client.ScheduleIntervalSpec{}, fmt.Errorf("invalid interval %q: offset must be at least zero and less than the interval", s)
+		}
+	}
+	return spec, nil
+}
+
+// FormatInterval returns spec in the form accepted by ParseInterval.
+func FormatInterval(spec client.ScheduleIntervalSpec) string {
+	if spec.Offset == 0 {
+		return formatDuration(spec.Every)
+	}
+	return formatDuration(spec.Every) + "/" + formatDuration(spec.Offset)
+}
+
+// formatDuration returns d without the zero units time.Duration.String adds, "1h" instead of "1h0m0s".
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// calendarField describes a field of client.ScheduleCalendarSpec.
+type calendarField struct {
+	name     string
+	min, max int
+	ranges   func(spec *client.ScheduleCalendarSpec) *[]client.ScheduleRange
+}
+
+var calendarFields = []calendarField{
+	{"second", 0, 59, func(spec *client.ScheduleCalendarSpec) *[]client.ScheduleRange { return &spec.Second }},
+	{"minute", 0, 59, func(spec *client.ScheduleCalendarSpec) *[]client.ScheduleRange { return &spec.Minute }},
+	{"hour", 0, 23, func(spec *client.ScheduleCalendarSpec) *[]client.ScheduleRange { return &spec.Hour }},
+	{"day_of_month", 1, 31, func(spec *client.ScheduleCalendarSpec) *[]client.ScheduleRange { return &spec.DayOfMonth }},
+	{"month", 1, 12, func(spec *client.ScheduleCalendarSpec) *[]client.ScheduleRange { return &spec.Month }},
+	{"year", 1970, 9999, func(spec *client.ScheduleCalendarSpec) *[]client.ScheduleRange { return &spec.Year }},
+	{"day_of_week", 0, 6, func(spec *client.ScheduleCalendarSpec) *[]client.ScheduleRange { return &spec.DayOfWeek }},
+}
+
+// ParseCalendar parses a calendar written as field=ranges pairs separated by semicolons.
+func ParseCalendar(s string) (client.ScheduleCalendarSpec, error) {
+	var spec client.ScheduleCalendarSpec
+	seen := map[string]bool{}
+	for _, pair := range strings.Split(s, ";") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !ok {
+			return client.ScheduleCalendarSpec{}, fmt.Errorf("invalid calendar %q: %q is not a field=ranges pair", s, pair)
+		}
+		field, ok := findCalendarField(name)
+		if !ok {
+			return client.ScheduleCalendarSpec{}, fmt.Errorf("invalid calendar %q: unknown field %q", s, name)
+		}
+		if seen[name] {
+			return client.ScheduleCalendarSpec{}, fmt.Errorf("invalid calendar %q: field %q is given more than once", s, name)
+		}
+		seen[name] = true
+		ranges, err := parseRanges(field, value)
+		if err != nil {
+			return client.ScheduleCalendarSpec{}, fmt.Errorf("invalid calendar %q: %w", s, err)
+		}
+		*field.ranges(&spec) = ranges
+	}
+	if len(seen) == 0 {
+		return client.ScheduleCalendarSpec{}, fmt.Errorf("invalid calendar %q: no fields", s)
+	}
+	return spec, nil
+}
+
+// FormatCalendar returns spec in the form accepted by ParseCalendar.
+// The Comment of spec is not included.
+func FormatCalendar(spec client.ScheduleCalendarSpec) string {
+	var pairs []string
+	for _, field := range calendarFields {
+		ranges := *field.ranges(&spec)
+		if len(ranges) == 0 {
+			continue
+		}
+		values := make([]string, len(ranges))
+		for i, r := range ranges {
+			values[i] = formatRange(r)
+		}
+		pairs = append(pairs, field.name+"="+strings.Join(values, ","))
+	}
+	return strings.Join(pairs, ";")
+}
+
+// CalendarFieldNames returns the names of the calendar fields, sorted.
+func CalendarFieldNames() []string {
+	names := make([]string, len(calendarFields))
+	for i, field := range calendarFields {
+		names[i] = field.name
+	}
+	sort.Strings(names)
+	return names
+}
+
+func findCalendarField(name string) (calendarField, bool) {
+	for _, field := range calendarFields {
+		if field.name == name {
+			return field, true
+		}
+	}
+	return calendarField{}, false
+}
+
+func parseRanges(field calendarField, value string) ([]client.ScheduleRange, error) {
+	var ranges []client.ScheduleRange
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		r, err := parseRange(field, part)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+func parseRange(field calendarField, s string) (client.ScheduleRange, error) {
+	bounds, step, hasStep := strings.Cut(s, "/")
+	var r client.ScheduleRange
+	var err error
+	if bounds == "*" {
+		r.Start, r.End = field.min, field.max
+	} else {
+		start, end, hasEnd := strings.Cut(bounds, "-")
+		r.Start, err = strconv.Atoi(start)
+		if err != nil {
+			return client.ScheduleRange{}, fmt.Errorf("%s: invalid range %q", field.name, s)
+		}
+		if hasEnd {
+			r.End, err = strconv.Atoi(end)
+			if err != nil {
+				return client.ScheduleRange{}, fmt.Errorf("%s: invalid range %q", field.name, s)
+			}
+		}
+	}
+	if hasStep {
+		r.Step, err = strconv.Atoi(step)
+		if err != nil || r.Step < 1 {
+			return client.ScheduleRange{}, fmt.Errorf("%s: invalid step in %q", field.name, s)
+		}
+		if r.End == 0 {
+			r.End = field.max
+		}
+	}
+	if r.Start < field.min || r.Start > field.max || (r.End != 0 && (r.End < r.Start || r.End > field.max)) {
+		return client.ScheduleRange{}, fmt.Errorf("%s: range %q is outside %d-%d", field.name, s, field.min, field.max)
+	}
+	return r, nil
+}
+
+func formatRange(r client.ScheduleRange) string {
+	s := strconv.Itoa(r.Start)
+	if r.End > r.Start {
+		s += "-" + strconv.Itoa(r.End)
+	}
+	if r.Step > 1 {
+		s += "/" + strconv.Itoa(r.Step)
+	}
+	return s
+}
+
+// CanonicalSpec returns spec as the Temporal Service stores it, which is how Describe returns it:
+// cron expressions are turned into calendars and intervals, and every calendar is made canonical.
+func CanonicalSpec(spec client.ScheduleSpec) (client.ScheduleSpec, error) {
+	out := spec
+	out.CronExpressions = nil
+	out.Calendars = canonicalCalendars(spec.Calendars)
+	out.Intervals = append([]client.ScheduleIntervalSpec(nil), spec.Intervals...)
+	out.Skip = canonicalCalendars(spec.Skip)
+	for _, expr := range spec.CronExpressions {
+		cron, err := ParseCron(expr)
+		if err != nil {
+			return client.ScheduleSpec{}, err
+		}
+		if cron.TimeZoneName != "" {
+			if out.TimeZoneName != "" && out.TimeZoneName != cron.TimeZoneName {
+				return client.ScheduleSpec{}, fmt.Errorf("cron %q: time zone %s conflicts with %s", expr, cron.TimeZoneName, out.TimeZoneName)
+			}
+			out.TimeZoneName = cron.TimeZoneName
+		}
+		out.Calendars = append(out.Calendars, canonicalCalendars(cron.Calendars)...)
+		out.Intervals = append(out.Intervals, cron.Intervals...)
+	}
+	return out, nil
+}
+
+// CanonicalCalendar fills in the fields of spec that are left out, as the SDK does when it sends the spec,
+// and writes every range with an explicit end and step.
+// Two calendars that match the same times and are written the same way have the same canonical form.
+func CanonicalCalendar(spec client.ScheduleCalendarSpec) client.ScheduleCalendarSpec {
+	defaults := map[string][]client.ScheduleRange{
+		"second":       {{Start: 0}},
+		"minute":       {{Start: 0}},
+		"hour":         {{Start: 0}},
+		"day_of_month": {{Start: 1, End: 31}},
+		"month":        {{Start: 1, End: 12}},
+		"day_of_week":  {{Start: 0, End: 6}},
+	}
+	out := client.ScheduleCalendarSpec{Comment: spec.Comment}
+	for _, field := range calendarFields {
+		ranges := *field.ranges(&spec)
+		if len(ranges) == 0 {
+			ranges = defaults[field.name]
+		}
+		var canonical []client.ScheduleRange
+		for _, r := range ranges {
+			if r.End < r.Start {
+				r.End = r.Start
+			}
+			if r.Step < 1 {
+				r.Step = 1
+			}
+			canonical = append(canonical, r)
+		}
+		*field.ranges(&out) = canonical
+	}
+	return out
+}
+
+func canonicalCalendars(specs []client.ScheduleCalendarSpec) []client.ScheduleCalendarSpec {
+	var out []client.ScheduleCalendarSpec
+	for _, spec := range specs {
+		out = append(out, CanonicalCalendar(spec))
+	}
+	return out
+}
@@ -0,0 +1,72 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/client"
+)
+
+func TestParseOverlapPolicy(t *testing.T) {
+	policy, err := ParseOverlapPolicy("buffer_one")
+	require.NoError(t, err)
+	require.Equal(t, enums.SCHEDULE_OVERLAP_POLICY_BUFFER_ONE, policy)
+	require.Equal(t, "buffer_one", FormatOverlapPolicy(policy))
+
+	policy, err = ParseOverlapPolicy("")
+	require.NoError(t, err)
+	require.Equal(t, enums.SCHEDULE_OVERLAP_POLICY_UNSPECIFIED, policy)
+
+	_, err = ParseOverlapPolicy("sometimes")
+	require.Error(t, err)
+}
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    client.ScheduleIntervalSpec
+		wantErr bool
+	}{
+		{in: "1h", want: client.ScheduleIntervalSpec{Every: time.Hour}},
+		{in: "1h/15m", want: client.ScheduleIntervalSpec{Every: time.Hour, Offset: 15 * time.Minute}},
+		{in: "0s", wantErr: true},
+		{in: "1h/1h", wantErr: true},
+		{in: "hourly", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseInterval(tt.in)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.in, FormatInterval(got))
+		})
+	}
+}
+
+func TestParseCalendar(t *testing.T) {
+	got, err := ParseCalendar("hour=9;minute=30;day_of_week=1-5")
+	require.NoError(t, err)
+	require.Equal(t, client.ScheduleCalendarSpec{
+		Minute:    []client.ScheduleRange{{Start: 30}},
+		Hour:      []client.ScheduleRange{{Start: 9}},
+		DayOfWeek: []client.ScheduleRange{{Start: 1, End: 5}},
+	}, got)
+	require.Equal(t, "minute=30;hour=9;day_of_week=1-5", FormatCalendar(got))
+
+	got, err = ParseCalendar("minute=*/15;day_of_month=1,15")
+	require.NoError(t, err)
+	require.Equal(t, []client.ScheduleRange{{Start: 0, End: 59, Step: 15}}, got.Minute)
+	require.Equal(t, []client.ScheduleRange{{Start: 1}, {Start: 15}}, got.DayOfMonth)
+	require.Equal(t, "minute=0-59/15;day_of_month=1,15", FormatCalendar(got))
+
+	for _, in := range []string{"", "hour", "hour=24", "hour=5-3", "hour=1;hour=2", "fortnight=1", "minute=*/0"} {
+		_, err := ParseCalendar(in)
+		require.Error(t, err, in)
+	}
+}
@@ -3,15 +3,34 @@ package main
 import (
 	"log"
 
+	"documentation-samples-go/codec"
+	"documentation-samples-go/metrics"
 	"github.com/temporalio/documentation-samples-go/schedule"
 	"go.temporal.io/sdk/client"
 	"go.temporal.io/sdk/worker"
 )
 
 func main() {
+	// Report SDK metrics to Prometheus on /metrics.
+	m, err := metrics.New()
+	if err != nil {
+		log.Fatalln("Unable to create metrics", err)
+	}
+	defer m.Close()
+	go func() {
+		log.Println("Metrics server stopped", m.ListenAndServe(":9091"))
+	}()
+
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		log.Fatalln("Unable to create Data Converter", err)
+	}
 	// The client and worker are heavyweight objects that should be created once per process.
 	c, err := client.Dial(client.Options{
-		HostPort: client.DefaultHostPort,
+		HostPort:       client.DefaultHostPort,
+		MetricsHandler: m.Handler(),
+		DataConverter:  dataConverter,
 	})
 	if err != nil {
 		log.Fatalln("Unable to create client", err)
@@ -9,6 +9,31 @@ import (
 	"go.temporal.io/sdk/workflow"
 )
 
+// InvocationSource is how a ScheduleWorkflow Execution was started.
+type InvocationSource string
+
+const (
+	// InvokedBySchedule means a Schedule started the Workflow Execution, as an Action, a trigger or a backfill.
+	InvokedBySchedule InvocationSource = "schedule"
+	// InvokedManually means the Workflow Execution was started directly, for example by a Client or a test.
+	InvokedManually InvocationSource = "manual"
+)
+
+// Search Attributes that the Temporal Service adds to Workflow Executions started by a Schedule.
+const (
+	ScheduledByIDSearchAttribute      = "TemporalScheduledById"
+	ScheduledStartTimeSearchAttribute = "TemporalScheduledStartTime"
+)
+
+// ScheduleSomethingParam is the input of ScheduleSomething.
+type ScheduleSomethingParam struct {
+	Source InvocationSource
+	// ScheduledByID is the Schedule Id, or the Workflow Id when the Workflow was started manually.
+	ScheduledByID string
+	// StartTime is the time the Schedule meant the Workflow to start, or the time it started when it was started manually.
+	StartTime time.Time
+}
+
 // ScheduleWorkflow executes on the given schedule
 func ScheduleWorkflow(ctx workflow.Context) error {
 
@@ -19,35 +44,54 @@ func ScheduleWorkflow(ctx workflow.Context) error {
 	}
 	ctx1 := workflow.WithActivityOptions(ctx, ao)
 
-	info := workflow.GetInfo(ctx1)
-
-	// Workflow Executions started by a Schedule have the following additional properties appended to their search attributes
-	scheduledByIDPayload := info.SearchAttributes.IndexedFields["TemporalScheduledById"]
-	var scheduledByID string
-	err := converter.GetDefaultDataConverter().FromPayload(scheduledByIDPayload, &scheduledByID)
+	param, err := invocation(ctx1)
 	if err != nil {
 		return err
 	}
 
-	startTimePayload := info.SearchAttributes.IndexedFields["TemporalScheduledStartTime"]
-	var startTime time.Time
-	err = converter.GetDefaultDataConverter().FromPayload(startTimePayload, &startTime)
+	err = workflow.ExecuteActivity(ctx1, ScheduleSomething, param).Get(ctx, nil)
 	if err != nil {
+		workflow.GetLogger(ctx).Error("schedule workflow failed.", "Error", err)
 		return err
 	}
 
-	err = workflow.ExecuteActivity(ctx1, ScheduleSomething, scheduledByID, startTime).Get(ctx, nil)
+	return nil
+}
+
+// invocation works out how the Workflow Execution was started.
+// Workflow Executions started by a Schedule have the Schedule Id and scheduled start time in their Search Attributes.
+// Others fall back to their Workflow Id and the current Workflow time.
+func invocation(ctx workflow.Context) (ScheduleSomethingParam, error) {
+	info := workflow.GetInfo(ctx)
+	fields := info.SearchAttributes.GetIndexedFields()
+
+	scheduledByIDPayload, ok := fields[ScheduledByIDSearchAttribute]
+	if !ok {
+		return ScheduleSomethingParam{
+			Source:        InvokedManually,
+			ScheduledByID: info.WorkflowExecution.ID,
+			StartTime:     workflow.Now(ctx),
+		}, nil
+	}
+	param := ScheduleSomethingParam{Source: InvokedBySchedule}
+	err := converter.GetDefaultDataConverter().FromPayload(scheduledByIDPayload, &param.ScheduledByID)
 	if err != nil {
-		workflow.GetLogger(ctx).Error("schedule workflow failed.", "Error", err)
-		return err
+		return ScheduleSomethingParam{}, err
 	}
 
-	return nil
+	param.StartTime = workflow.Now(ctx)
+	if startTimePayload, ok := fields[ScheduledStartTimeSearchAttribute]; ok {
+		err = converter.GetDefaultDataConverter().FromPayload(startTimePayload, &param.StartTime)
+		if err != nil {
+			return ScheduleSomethingParam{}, err
+		}
+	}
+	return param, nil
 }
 
 // ScheduleSomething is an Activity
-func ScheduleSomething(ctx context.Context, scheduleByID string, startTime time.Time) error {
-	activity.GetLogger(ctx).Info("Schedule job running.", "scheduleByID", scheduleByID, "startTime", startTime)
+func ScheduleSomething(ctx context.Context, param ScheduleSomethingParam) error {
+	activity.GetLogger(ctx).Info("Schedule job running.", "source", param.Source, "scheduleByID", param.ScheduledByID, "startTime", param.StartTime)
 	// Query database, call external API, or do any other non-deterministic action.
 	return nil
 }
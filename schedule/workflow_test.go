@@ -0,0 +1,61 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+)
+
+// runScheduleWorkflow runs ScheduleWorkflow with the given Search Attributes, and returns the input of ScheduleSomething.
+func runScheduleWorkflow(t *testing.T, startTime time.Time, searchAttributes map[string]interface{}) ScheduleSomethingParam {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.SetStartTime(startTime)
+	if searchAttributes != nil {
+		require.NoError(t, env.SetSearchAttributesOnStart(searchAttributes))
+	}
+	var param ScheduleSomethingParam
+	env.RegisterActivity(ScheduleSomething)
+	env.OnActivity(ScheduleSomething, mock.Anything, mock.Anything).Return(func(_ context.Context, p ScheduleSomethingParam) error {
+		param = p
+		return nil
+	})
+
+	env.ExecuteWorkflow(ScheduleWorkflow)
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	return param
+}
+
+func TestScheduleWorkflow_StartedManually(t *testing.T) {
+	startTime := time.Date(2023, 5, 1, 9, 30, 0, 0, time.UTC)
+	param := runScheduleWorkflow(t, startTime, nil)
+	require.Equal(t, InvokedManually, param.Source)
+	require.Equal(t, "default-test-workflow-id", param.ScheduledByID)
+	require.True(t, startTime.Equal(param.StartTime), "start time %s", param.StartTime)
+}
+
+func TestScheduleWorkflow_StartedBySchedule(t *testing.T) {
+	scheduledTime := time.Date(2023, 5, 1, 9, 30, 0, 0, time.UTC)
+	// The Schedule started the Workflow a little after its scheduled time.
+	param := runScheduleWorkflow(t, scheduledTime.Add(3*time.Second), map[string]interface{}{
+		ScheduledByIDSearchAttribute:      "my-schedule",
+		ScheduledStartTimeSearchAttribute: scheduledTime,
+	})
+	require.Equal(t, InvokedBySchedule, param.Source)
+	require.Equal(t, "my-schedule", param.ScheduledByID)
+	require.True(t, scheduledTime.Equal(param.StartTime), "start time %s", param.StartTime)
+}
+
+func TestScheduleWorkflow_RunsActivity(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterActivity(ScheduleSomething)
+	env.ExecuteWorkflow(ScheduleWorkflow)
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+}
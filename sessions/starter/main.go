@@ -6,12 +6,19 @@ import (
 
 	"go.temporal.io/sdk/client"
 
+	"documentation-samples-go/codec"
 	"documentation-samples-go/sessions"
 )
 
 func main() {
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		log.Fatalln("Unable to create Data Converter", err)
+	}
 	temporalClient, err := client.Dial(client.Options{
-		HostPort: client.DefaultHostPort,
+		HostPort:      client.DefaultHostPort,
+		DataConverter: dataConverter,
 	})
 	if err != nil {
 		log.Fatalln("Unable to create client", err)
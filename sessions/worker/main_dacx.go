@@ -6,6 +6,8 @@ import (
 	"go.temporal.io/sdk/client"
 	"go.temporal.io/sdk/worker"
 
+	"documentation-samples-go/codec"
+	"documentation-samples-go/metrics"
 	"documentation-samples-go/sessions"
 )
 
@@ -14,9 +16,25 @@ Set `EnableSessionWorker` to `true` in the Worker options.
 */
 
 func main() {
+	// Report SDK metrics to Prometheus on /metrics.
+	workerMetrics, err := metrics.New()
+	if err != nil {
+		log.Fatalln("Unable to create metrics", err)
+	}
+	defer workerMetrics.Close()
+	go func() {
+		log.Println("Metrics server stopped", workerMetrics.ListenAndServe(":9092"))
+	}()
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		log.Fatalln("Unable to create Data Converter", err)
+	}
 	// The client and worker are heavyweight objects that should be created once per process.
 	temporalClient, err := client.Dial(client.Options{
-		HostPort: client.DefaultHostPort,
+		HostPort:       client.DefaultHostPort,
+		MetricsHandler: workerMetrics.Handler(),
+		DataConverter:  dataConverter,
 	})
 	if err != nil {
 		log.Fatalln("Unable to create client", err)
@@ -56,7 +74,7 @@ tags:
 - code sample
 - session
 - worker
-lines: 12-14, 16, 25-27, 31-35, 39
+lines: 14-16, 18, 43-45, 49-53, 57
 @dacx */
 
 /* @dacx
@@ -69,5 +87,5 @@ tags:
 - code sample
 - session
 - worker
-lines: 16, 26, 28-30, 39, 41-47
+lines: 18, 44, 46-48, 57, 59-65
 @dacx */
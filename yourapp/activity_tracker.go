@@ -0,0 +1,61 @@
+package yourapp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"go.temporal.io/sdk/interceptor"
+)
+
+/*
+A Worker that shuts down waits up to `worker.Options.WorkerStopTimeout` for its running Activities, and then cancels them.
+`worker.Stop()` returns in both cases, so the Worker Process can't tell from it whether every Activity finished.
+The ActivityTracker below is a Worker Interceptor that counts the Activity Executions that are running,
+and those that returned after their context was canceled.
+*/
+
+// ActivityTracker counts the Activity Executions of a Worker.
+type ActivityTracker struct {
+	interceptor.WorkerInterceptorBase
+	running  atomic.Int64
+	canceled atomic.Int64
+}
+
+// NewActivityTracker returns an ActivityTracker. Pass it to the Worker with worker.Options.Interceptors.
+func NewActivityTracker() *ActivityTracker {
+	return &ActivityTracker{}
+}
+
+// Running returns the number of Activity Executions that are running.
+func (t *ActivityTracker) Running() int64 {
+	return t.running.Load()
+}
+
+// Canceled returns the number of Activity Executions that returned after their context was canceled,
+// either by their Workflow or by the Worker when they outlived WorkerStopTimeout.
+func (t *ActivityTracker) Canceled() int64 {
+	return t.canceled.Load()
+}
+
+// InterceptActivity implements interceptor.WorkerInterceptor.
+func (t *ActivityTracker) InterceptActivity(ctx context.Context, next interceptor.ActivityInboundInterceptor) interceptor.ActivityInboundInterceptor {
+	a := &trackingActivityInboundInterceptor{tracker: t}
+	a.Next = next
+	return a
+}
+
+type trackingActivityInboundInterceptor struct {
+	interceptor.ActivityInboundInterceptorBase
+	tracker *ActivityTracker
+}
+
+func (a *trackingActivityInboundInterceptor) ExecuteActivity(ctx context.Context, in *interceptor.ExecuteActivityInput) (interface{}, error) {
+	a.tracker.running.Add(1)
+	defer a.tracker.running.Add(-1)
+	result, err := a.Next.ExecuteActivity(ctx, in)
+	if errors.Is(ctx.Err(), context.Canceled) {
+		a.tracker.canceled.Add(1)
+	}
+	return result, err
+}
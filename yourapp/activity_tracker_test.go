@@ -0,0 +1,81 @@
+package yourapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+// trackedActivities report what an ActivityTracker sees while they run.
+type trackedActivities struct {
+	tracker *ActivityTracker
+}
+
+// Running returns the number of running Activities, including itself.
+func (a *trackedActivities) Running() (int64, error) {
+	return a.tracker.Running(), nil
+}
+
+// WaitForCancel heartbeats until it is canceled.
+func (a *trackedActivities) WaitForCancel(ctx context.Context) error {
+	for {
+		activity.RecordHeartbeat(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// trackedWorkflow runs Running, and then cancels WaitForCancel.
+func trackedWorkflow(ctx workflow.Context) (int64, error) {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		HeartbeatTimeout:    time.Second,
+		WaitForCancellation: true,
+	})
+	var a *trackedActivities
+	var running int64
+	if err := workflow.ExecuteActivity(ctx, a.Running).Get(ctx, &running); err != nil {
+		return 0, err
+	}
+	cancelCtx, cancel := workflow.WithCancel(ctx)
+	future := workflow.ExecuteActivity(cancelCtx, a.WaitForCancel)
+	if err := workflow.Sleep(ctx, time.Second); err != nil {
+		return 0, err
+	}
+	cancel()
+	if err := future.Get(ctx, nil); !temporal.IsCanceledError(err) {
+		return 0, err
+	}
+	return running, nil
+}
+
+func Test_ActivityTracker(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	tracker := NewActivityTracker()
+	env.SetWorkerOptions(worker.Options{
+		Interceptors: []interceptor.WorkerInterceptor{tracker},
+	})
+	env.RegisterActivity(&trackedActivities{tracker: tracker})
+	env.ExecuteWorkflow(trackedWorkflow)
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var running int64
+	require.NoError(t, env.GetWorkflowResult(&running))
+	require.Equal(t, int64(1), running)
+	// The test environment reports the cancellation before the canceled Activity returns.
+	require.Eventually(t, func() bool {
+		return tracker.Running() == 0 && tracker.Canceled() == 1
+	}, time.Second, 10*time.Millisecond)
+}
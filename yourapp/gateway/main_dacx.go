@@ -6,9 +6,13 @@ import (
 	"log"
 	"net/http"
 
+	"documentation-samples-go/codec"
 	"documentation-samples-go/yourapp"
 
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/interceptor"
 )
 
 /*
@@ -20,19 +24,29 @@ If you don't set a custom Namespace name in the Namespace field, the client conn
 */
 
 func main() {
+	// Trace each request and the Workflow Execution it starts.
+	tracerProvider, tracingInterceptor := newTracing()
+	defer tracerProvider.Shutdown(context.Background())
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		log.Fatalln("Unable to create Data Converter", err)
+	}
 	// Create a Temporal Client to communicate with the Temporal Cluster.
 	// A Temporal Client is a heavyweight object that should be created just once per process.
 	temporalClient, err := client.Dial(client.Options{
-		HostPort: client.DefaultHostPort,
+		HostPort:      client.DefaultHostPort,
+		Interceptors:  []interceptor.ClientInterceptor{tracingInterceptor},
+		DataConverter: dataConverter,
 	})
 	if err != nil {
 		log.Fatalln("Unable to create Temporal Client", err)
 	}
 	defer temporalClient.Close()
 	// Start an HTTP server and listen on /start
-	http.HandleFunc("/start", func(w http.ResponseWriter, r *http.Request) {
+	http.Handle("/start", yourapp.TraceHTTP(tracerProvider, "GET /start", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		startWorkflowHandler(w, r, temporalClient)
-	})
+	})))
 	err = http.ListenAndServe(":8091", nil)
 	if err != nil {
 		log.Fatalln("Unable to run http server", err)
@@ -54,8 +68,9 @@ func startWorkflowHandler(w http.ResponseWriter, r *http.Request, temporalClient
 		WorkflowParamY: 999,
 	}
 	// Make the call to the Temporal Cluster to start the Workflow Execution.
+	// The request context carries the trace, so the Workflow spans become its children.
 	workflowExecution, err := temporalClient.ExecuteWorkflow(
-		context.Background(),
+		r.Context(),
 		workflowOptions,
 		yourapp.YourWorkflowDefinition,
 		workflowParams,
@@ -79,6 +94,20 @@ func startWorkflowHandler(w http.ResponseWriter, r *http.Request, temporalClient
 	log.Println(string(b))
 }
 
+// newTracing returns a TracerProvider that writes spans to stdout and the Temporal tracing interceptor that uses it.
+func newTracing() (*sdktrace.TracerProvider, interceptor.Interceptor) {
+	exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+	if err != nil {
+		log.Fatalln("Unable to create trace exporter", err)
+	}
+	tracerProvider := yourapp.NewTracerProvider("yourapp-gateway", exporter)
+	tracingInterceptor, err := yourapp.NewTracingInterceptor(tracerProvider)
+	if err != nil {
+		log.Fatalln("Unable to create tracing interceptor", err)
+	}
+	return tracerProvider, tracingInterceptor
+}
+
 /* @dacx
 id: how-to-connect-to-a-development-cluster-in-go
 title: How to connect to a Temporal dev Cluster in Go
@@ -88,5 +117,5 @@ tags:
 - go sdk
 - code sample
 - cluster
-lines: 1-31, 40
+lines: 1-11, 14, 16-26, 35-38, 41-45, 54
 @dacx */
@@ -0,0 +1,78 @@
+package yourapp
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"go.temporal.io/sdk/client"
+)
+
+/*
+An orchestrator, such as Kubernetes, needs two answers from a Worker Process:
+
+- Is the process alive? (`/healthz`)
+- Is the Worker connected to the Temporal Cluster and polling its Task Queue? (`/readyz`)
+
+The liveness probe only reports that the HTTP listener is up.
+The readiness probe calls [`CheckHealth()`](https://pkg.go.dev/go.temporal.io/sdk/client#Client) on the Temporal Client and also checks that the Worker has started and is not draining.
+*/
+
+// HealthCheckTimeout bounds how long a readiness probe waits on the Temporal Cluster.
+const HealthCheckTimeout = 5 * time.Second
+
+// WorkerHealth tracks the state of a Worker Process for the health endpoints.
+type WorkerHealth struct {
+	client   client.Client
+	started  atomic.Bool
+	draining atomic.Bool
+}
+
+// NewWorkerHealth returns a WorkerHealth that checks the given Temporal Client.
+func NewWorkerHealth(temporalClient client.Client) *WorkerHealth {
+	return &WorkerHealth{client: temporalClient}
+}
+
+// MarkStarted records that the Worker has started polling.
+func (h *WorkerHealth) MarkStarted() {
+	h.started.Store(true)
+}
+
+// MarkDraining records that the Worker has stopped polling and is waiting on running Activities.
+func (h *WorkerHealth) MarkDraining() {
+	h.draining.Store(true)
+}
+
+// Handler returns an http.Handler that serves /healthz and /readyz.
+func (h *WorkerHealth) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", h.healthz)
+	mux.HandleFunc("/readyz", h.readyz)
+	return mux
+}
+
+func (h *WorkerHealth) healthz(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok\n"))
+}
+
+func (h *WorkerHealth) readyz(w http.ResponseWriter, r *http.Request) {
+	if h.draining.Load() {
+		http.Error(w, "worker is draining", http.StatusServiceUnavailable)
+		return
+	}
+	if !h.started.Load() {
+		http.Error(w, "worker has not started", http.StatusServiceUnavailable)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), HealthCheckTimeout)
+	defer cancel()
+	_, err := h.client.CheckHealth(ctx, &client.CheckHealthRequest{})
+	if err != nil {
+		http.Error(w, "temporal health check failed: "+err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok\n"))
+}
@@ -0,0 +1,46 @@
+package yourapp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/mocks"
+)
+
+func Test_WorkerHealth(t *testing.T) {
+	temporalClient := &mocks.Client{}
+	health := NewWorkerHealth(temporalClient)
+	server := httptest.NewServer(health.Handler())
+	defer server.Close()
+
+	get := func(path string) int {
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// The process is alive before the Worker starts, but not ready.
+	require.Equal(t, http.StatusOK, get("/healthz"))
+	require.Equal(t, http.StatusServiceUnavailable, get("/readyz"))
+
+	// Ready once the Worker has started and the Cluster is reachable.
+	health.MarkStarted()
+	call := temporalClient.On("CheckHealth", mock.Anything, mock.Anything).Return(&client.CheckHealthResponse{}, nil)
+	require.Equal(t, http.StatusOK, get("/readyz"))
+
+	// Not ready while the Cluster is unreachable.
+	call.Unset()
+	temporalClient.On("CheckHealth", mock.Anything, mock.Anything).Return(nil, errors.New("unavailable"))
+	require.Equal(t, http.StatusServiceUnavailable, get("/readyz"))
+
+	// Not ready while draining, but still alive.
+	health.MarkDraining()
+	require.Equal(t, http.StatusServiceUnavailable, get("/readyz"))
+	require.Equal(t, http.StatusOK, get("/healthz"))
+}
@@ -0,0 +1,120 @@
+package yourapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+/*
+A Worker Interceptor wraps every Workflow Execution and Activity Execution that the Worker runs.
+Pass it to the Worker with `worker.Options.Interceptors`.
+
+The interceptor below writes one record when a Workflow Execution or Activity Execution finishes.
+A Workflow Execution gets a single record for each run, however many Workflow Tasks it took:
+the SDK has no interceptor that runs once per Workflow Task, only ExecuteWorkflow, which spans the whole run.
+Each Activity attempt gets its own record.
+Each record carries the Workflow Id, Run Id, Workflow or Activity Type, Attempt, duration, outcome and error type.
+Records are written through `workflow.GetLogger()` and `activity.GetLogger()`.
+The Workflow logger is replay-safe, so a record is not written again when the Workflow is replayed.
+*/
+
+// Outcomes recorded by the LoggingInterceptor.
+const (
+	OutcomeCompleted      = "Completed"
+	OutcomeFailed         = "Failed"
+	OutcomeCanceled       = "Canceled"
+	OutcomeContinuedAsNew = "ContinuedAsNew"
+)
+
+// LoggingInterceptor writes a structured record for each Workflow Execution run and each Activity attempt.
+type LoggingInterceptor struct {
+	interceptor.WorkerInterceptorBase
+}
+
+// NewLoggingInterceptor returns a Worker Interceptor that logs Workflow and Activity Executions.
+func NewLoggingInterceptor() interceptor.WorkerInterceptor {
+	return &LoggingInterceptor{}
+}
+
+// InterceptActivity implements interceptor.WorkerInterceptor.
+func (i *LoggingInterceptor) InterceptActivity(ctx context.Context, next interceptor.ActivityInboundInterceptor) interceptor.ActivityInboundInterceptor {
+	a := &loggingActivityInboundInterceptor{}
+	a.Next = next
+	return a
+}
+
+// InterceptWorkflow implements interceptor.WorkerInterceptor.
+func (i *LoggingInterceptor) InterceptWorkflow(ctx workflow.Context, next interceptor.WorkflowInboundInterceptor) interceptor.WorkflowInboundInterceptor {
+	w := &loggingWorkflowInboundInterceptor{}
+	w.Next = next
+	return w
+}
+
+type loggingActivityInboundInterceptor struct {
+	interceptor.ActivityInboundInterceptorBase
+}
+
+func (a *loggingActivityInboundInterceptor) ExecuteActivity(ctx context.Context, in *interceptor.ExecuteActivityInput) (interface{}, error) {
+	start := time.Now()
+	result, err := a.Next.ExecuteActivity(ctx, in)
+	outcome, errorType := classifyError(err)
+	info := activity.GetInfo(ctx)
+	activity.GetLogger(ctx).Info("Activity execution finished",
+		"WorkflowID", info.WorkflowExecution.ID,
+		"RunID", info.WorkflowExecution.RunID,
+		"ActivityType", info.ActivityType.Name,
+		"Attempt", info.Attempt,
+		"Duration", time.Since(start),
+		"Outcome", outcome,
+		"ErrorType", errorType,
+	)
+	return result, err
+}
+
+type loggingWorkflowInboundInterceptor struct {
+	interceptor.WorkflowInboundInterceptorBase
+}
+
+// ExecuteWorkflow logs when the run finishes, not after each Workflow Task.
+func (w *loggingWorkflowInboundInterceptor) ExecuteWorkflow(ctx workflow.Context, in *interceptor.ExecuteWorkflowInput) (interface{}, error) {
+	// Use workflow.Now() so that the duration is deterministic.
+	start := workflow.Now(ctx)
+	result, err := w.Next.ExecuteWorkflow(ctx, in)
+	outcome, errorType := classifyError(err)
+	info := workflow.GetInfo(ctx)
+	workflow.GetLogger(ctx).Info("Workflow execution finished",
+		"WorkflowID", info.WorkflowExecution.ID,
+		"RunID", info.WorkflowExecution.RunID,
+		"WorkflowType", info.WorkflowType.Name,
+		"Attempt", info.Attempt,
+		"Duration", workflow.Now(ctx).Sub(start),
+		"Outcome", outcome,
+		"ErrorType", errorType,
+	)
+	return result, err
+}
+
+// classifyError returns the outcome and error type recorded for err.
+func classifyError(err error) (outcome string, errorType string) {
+	if err == nil {
+		return OutcomeCompleted, ""
+	}
+	if workflow.IsContinueAsNewError(err) {
+		return OutcomeContinuedAsNew, ""
+	}
+	if temporal.IsCanceledError(err) {
+		return OutcomeCanceled, "CanceledError"
+	}
+	var applicationErr *temporal.ApplicationError
+	if errors.As(err, &applicationErr) && applicationErr.Type() != "" {
+		return OutcomeFailed, applicationErr.Type()
+	}
+	return OutcomeFailed, fmt.Sprintf("%T", err)
+}
@@ -0,0 +1,164 @@
+package yourapp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/log"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+// capturedRecord is one log entry with its keyvals collected into a map.
+type capturedRecord struct {
+	Message string
+	Fields  map[string]interface{}
+}
+
+// captureLogger is a log.Logger that keeps every entry in memory.
+type captureLogger struct {
+	mu      sync.Mutex
+	records []capturedRecord
+}
+
+func (l *captureLogger) record(msg string, keyvals []interface{}) {
+	fields := map[string]interface{}{}
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		fields[keyvals[i].(string)] = keyvals[i+1]
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records = append(l.records, capturedRecord{Message: msg, Fields: fields})
+}
+
+func (l *captureLogger) Debug(msg string, keyvals ...interface{}) { l.record(msg, keyvals) }
+func (l *captureLogger) Info(msg string, keyvals ...interface{})  { l.record(msg, keyvals) }
+func (l *captureLogger) Warn(msg string, keyvals ...interface{})  { l.record(msg, keyvals) }
+func (l *captureLogger) Error(msg string, keyvals ...interface{}) { l.record(msg, keyvals) }
+
+func (l *captureLogger) withMessage(msg string) []capturedRecord {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var found []capturedRecord
+	for _, r := range l.records {
+		if r.Message == msg {
+			found = append(found, r)
+		}
+	}
+	return found
+}
+
+var _ log.Logger = (*captureLogger)(nil)
+
+func newLoggingTestEnvironment(logger log.Logger) *testsuite.TestWorkflowEnvironment {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	testSuite.SetLogger(logger)
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.SetWorkerOptions(worker.Options{
+		Interceptors: []interceptor.WorkerInterceptor{NewLoggingInterceptor()},
+	})
+	message := "Message"
+	number := 1
+	env.RegisterActivity(&YourActivityObject{Message: &message, Number: &number})
+	return env
+}
+
+func Test_LoggingInterceptor_Completed(t *testing.T) {
+	logger := &captureLogger{}
+	env := newLoggingTestEnvironment(logger)
+	env.ExecuteWorkflow(YourWorkflowDefinition, YourWorkflowParam{WorkflowParamX: "Hello World!", WorkflowParamY: 100})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	activityRecords := logger.withMessage("Activity execution finished")
+	require.Len(t, activityRecords, 3)
+	var activityTypes []interface{}
+	for _, r := range activityRecords {
+		activityTypes = append(activityTypes, r.Fields["ActivityType"])
+		require.Equal(t, OutcomeCompleted, r.Fields["Outcome"])
+		require.Equal(t, "", r.Fields["ErrorType"])
+		require.Equal(t, int32(1), r.Fields["Attempt"])
+		require.Equal(t, "default-test-workflow-id", r.Fields["WorkflowID"])
+		require.Equal(t, "default-test-run-id", r.Fields["RunID"])
+		require.Contains(t, r.Fields, "Duration")
+	}
+	require.Equal(t, []interface{}{"YourActivityDefinition", "GetInfo", "PrintInfo"}, activityTypes)
+
+	// The Workflow took a Workflow Task for each Activity, but is logged once, when it finishes.
+	workflowRecords := logger.withMessage("Workflow execution finished")
+	require.Len(t, workflowRecords, 1)
+	require.Equal(t, OutcomeCompleted, workflowRecords[0].Fields["Outcome"])
+	require.Equal(t, "YourWorkflowDefinition", workflowRecords[0].Fields["WorkflowType"])
+	require.Equal(t, "default-test-workflow-id", workflowRecords[0].Fields["WorkflowID"])
+	require.Equal(t, "default-test-run-id", workflowRecords[0].Fields["RunID"])
+}
+
+// failingActivity always fails with a non-retryable Application Error.
+func failingActivity() error {
+	return temporal.NewNonRetryableApplicationError("boom", "YourErrorType", errors.New("boom"))
+}
+
+// failingWorkflow runs failingActivity and returns its error.
+func failingWorkflow(ctx workflow.Context) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{StartToCloseTimeout: 10 * time.Second})
+	return workflow.ExecuteActivity(ctx, failingActivity).Get(ctx, nil)
+}
+
+func Test_LoggingInterceptor_Failed(t *testing.T) {
+	logger := &captureLogger{}
+	env := newLoggingTestEnvironment(logger)
+	env.RegisterActivity(failingActivity)
+	env.ExecuteWorkflow(failingWorkflow)
+	require.True(t, env.IsWorkflowCompleted())
+	require.Error(t, env.GetWorkflowError())
+
+	activityRecords := logger.withMessage("Activity execution finished")
+	require.Len(t, activityRecords, 1)
+	require.Equal(t, "failingActivity", activityRecords[0].Fields["ActivityType"])
+	require.Equal(t, OutcomeFailed, activityRecords[0].Fields["Outcome"])
+	require.Equal(t, "YourErrorType", activityRecords[0].Fields["ErrorType"])
+
+	workflowRecords := logger.withMessage("Workflow execution finished")
+	require.Len(t, workflowRecords, 1)
+	require.Equal(t, OutcomeFailed, workflowRecords[0].Fields["Outcome"])
+	require.NotEmpty(t, workflowRecords[0].Fields["ErrorType"])
+}
+
+// flakyActivity fails its first attempt.
+func flakyActivity(ctx context.Context) error {
+	if activity.GetInfo(ctx).Attempt == 1 {
+		return temporal.NewApplicationError("try again", "YourRetryableError")
+	}
+	return nil
+}
+
+// flakyWorkflow runs flakyActivity until it succeeds.
+func flakyWorkflow(ctx workflow.Context) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{StartToCloseTimeout: 10 * time.Second})
+	return workflow.ExecuteActivity(ctx, flakyActivity).Get(ctx, nil)
+}
+
+func Test_LoggingInterceptor_EachAttempt(t *testing.T) {
+	logger := &captureLogger{}
+	env := newLoggingTestEnvironment(logger)
+	env.RegisterActivity(flakyActivity)
+	env.ExecuteWorkflow(flakyWorkflow)
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	activityRecords := logger.withMessage("Activity execution finished")
+	require.Len(t, activityRecords, 2)
+	require.Equal(t, int32(1), activityRecords[0].Fields["Attempt"])
+	require.Equal(t, "YourRetryableError", activityRecords[0].Fields["ErrorType"])
+	require.Equal(t, int32(2), activityRecords[1].Fields["Attempt"])
+	require.Equal(t, OutcomeCompleted, activityRecords[1].Fields["Outcome"])
+	require.Len(t, logger.withMessage("Workflow execution finished"), 1)
+}
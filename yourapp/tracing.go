@@ -0,0 +1,64 @@
+package yourapp
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.temporal.io/sdk/contrib/opentelemetry"
+	"go.temporal.io/sdk/interceptor"
+)
+
+/*
+To follow a request from the HTTP gateway into the Workflow and Activity Executions it causes, use OpenTelemetry tracing.
+
+1. The gateway continues the trace found in the HTTP request headers and starts a server span.
+1. The tracing interceptor on the Client writes the span context into the Workflow headers when `ExecuteWorkflow()` is called.
+1. The same interceptor on the Worker reads the headers and starts the Workflow and Activity spans as children of that span.
+
+Set the interceptor in `client.Options.Interceptors`.
+Because it also implements `interceptor.WorkerInterceptor`, every Worker created from that Client uses it too.
+*/
+
+// TracerName is the name of the OpenTelemetry Tracer used by yourapp.
+const TracerName = "documentation-samples-go/yourapp"
+
+// Propagator reads and writes the W3C trace context and baggage, in HTTP headers and in Temporal headers.
+var Propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// NewTracerProvider returns a TracerProvider that exports spans for serviceName to exporter.
+func NewTracerProvider(serviceName string, exporter sdktrace.SpanExporter) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	)
+}
+
+// NewTracingInterceptor returns an interceptor that traces Workflows and Activities with tracerProvider.
+func NewTracingInterceptor(tracerProvider trace.TracerProvider) (interceptor.Interceptor, error) {
+	return opentelemetry.NewTracingInterceptor(opentelemetry.TracerOptions{
+		Tracer:            tracerProvider.Tracer(TracerName),
+		TextMapPropagator: Propagator,
+	})
+}
+
+// TraceHTTP wraps next so that each request runs in a server span named spanName.
+// The span continues the trace found in the request headers, if any.
+func TraceHTTP(tracerProvider trace.TracerProvider, spanName string, next http.Handler) http.Handler {
+	tracer := tracerProvider.Tracer(TracerName)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := Propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, spanName,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.method", r.Method),
+				attribute.String("http.target", r.URL.Path),
+			),
+		)
+		defer span.End()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
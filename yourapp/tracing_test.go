@@ -0,0 +1,106 @@
+package yourapp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+	"google.golang.org/grpc"
+)
+
+// fakeFrontend stands in for the Temporal Cluster and keeps the header of the last started Workflow.
+type fakeFrontend struct {
+	workflowservice.UnimplementedWorkflowServiceServer
+	header *commonpb.Header
+}
+
+func (f *fakeFrontend) StartWorkflowExecution(ctx context.Context, request *workflowservice.StartWorkflowExecutionRequest) (*workflowservice.StartWorkflowExecutionResponse, error) {
+	f.header = request.GetHeader()
+	return &workflowservice.StartWorkflowExecutionResponse{RunId: "your-run-id"}, nil
+}
+
+// startFakeFrontend serves frontend on a local port until the test ends.
+func startFakeFrontend(t *testing.T, frontend *fakeFrontend) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	workflowservice.RegisterWorkflowServiceServer(server, frontend)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+func Test_Tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracingInterceptor, err := NewTracingInterceptor(tracerProvider)
+	require.NoError(t, err)
+
+	// Start the Workflow from an HTTP request that already carries a trace.
+	frontend := &fakeFrontend{}
+	temporalClient, err := client.NewLazyClient(client.Options{
+		HostPort:     startFakeFrontend(t, frontend),
+		Interceptors: []interceptor.ClientInterceptor{tracingInterceptor},
+	})
+	require.NoError(t, err)
+	defer temporalClient.Close()
+	handler := TraceHTTP(tracerProvider, "GET /start", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := temporalClient.ExecuteWorkflow(r.Context(), client.StartWorkflowOptions{
+			ID:        "your-workflow-id",
+			TaskQueue: "your-custom-task-queue-name",
+		}, YourWorkflowDefinition, YourWorkflowParam{})
+		require.NoError(t, err)
+	}))
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	request := httptest.NewRequest(http.MethodGet, "/start", nil)
+	request.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+	require.NotEmpty(t, frontend.header.GetFields())
+
+	// Run the Workflow with the header that the Client sent.
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.SetWorkerOptions(worker.Options{
+		Interceptors: []interceptor.WorkerInterceptor{tracingInterceptor},
+	})
+	env.SetHeader(frontend.header)
+	message := "Message"
+	number := 1
+	env.RegisterActivity(&YourActivityObject{Message: &message, Number: &number})
+	env.ExecuteWorkflow(YourWorkflowDefinition, YourWorkflowParam{WorkflowParamX: "Hello World!", WorkflowParamY: 100})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		require.Equal(t, traceID, span.SpanContext().TraceID().String(), span.Name())
+		spans[span.Name()] = span
+	}
+	parentOf := func(name string) trace.SpanID {
+		span, ok := spans[name]
+		require.True(t, ok, "missing span %q", name)
+		return span.Parent().SpanID()
+	}
+	spanID := func(name string) trace.SpanID {
+		return spans[name].SpanContext().SpanID()
+	}
+
+	require.Equal(t, spanID("GET /start"), parentOf("StartWorkflow:YourWorkflowDefinition"))
+	require.Equal(t, spanID("StartWorkflow:YourWorkflowDefinition"), parentOf("RunWorkflow:YourWorkflowDefinition"))
+	for _, activityType := range []string{"YourActivityDefinition", "GetInfo", "PrintInfo"} {
+		require.Equal(t, spanID("RunWorkflow:YourWorkflowDefinition"), parentOf("StartActivity:"+activityType))
+		require.Equal(t, spanID("StartActivity:"+activityType), parentOf("RunActivity:"+activityType))
+	}
+}
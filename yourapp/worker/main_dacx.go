@@ -1,13 +1,23 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"log"
+	"net/http"
+	"os"
+	"time"
 
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
 	"go.temporal.io/sdk/activity"
 	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/interceptor"
 	"go.temporal.io/sdk/worker"
 	"go.temporal.io/sdk/workflow"
 
+	"documentation-samples-go/codec"
+	"documentation-samples-go/metrics"
 	"documentation-samples-go/yourapp"
 )
 
@@ -25,16 +35,87 @@ Run accepts an interrupt channel as a parameter, so that the Worker can be stopp
 Otherwise, the `Stop()` method must be called to stop the Worker.
 */
 
+// Exit codes reported by the Worker Process.
+const (
+	exitOK           = 0
+	exitStartFailure = 1
+	exitDrainTimeout = 2
+)
+
+var (
+	healthAddr   = flag.String("health-addr", ":8092", "address of the /healthz and /readyz listener")
+	metricsAddr  = flag.String("metrics-addr", ":9090", "address of the Prometheus /metrics listener")
+	drainTimeout = flag.Duration("drain-timeout", 30*time.Second, "how long to wait for running Activities on shutdown")
+)
+
 func main() {
+	flag.Parse()
+	os.Exit(run())
+}
+
+func run() int {
+	// Report SDK metrics to Prometheus.
+	workerMetrics, err := metrics.New()
+	if err != nil {
+		log.Println("Unable to create metrics", err)
+		return exitStartFailure
+	}
+	defer workerMetrics.Close()
+	go func() {
+		err := workerMetrics.ListenAndServe(*metricsAddr)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Println("Unable to run metrics server", err)
+		}
+	}()
+	// Trace Workflow and Activity Executions, continuing the trace started by the gateway.
+	exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+	if err != nil {
+		log.Println("Unable to create trace exporter", err)
+		return exitStartFailure
+	}
+	tracerProvider := yourapp.NewTracerProvider("yourapp-worker", exporter)
+	defer tracerProvider.Shutdown(context.Background())
+	tracingInterceptor, err := yourapp.NewTracingInterceptor(tracerProvider)
+	if err != nil {
+		log.Println("Unable to create tracing interceptor", err)
+		return exitStartFailure
+	}
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		log.Println("Unable to create Data Converter", err)
+		return exitStartFailure
+	}
 	// Create a Temporal Client
 	// A Temporal Client is a heavyweight object that should be created just once per process.
-	temporalClient, err := client.Dial(client.Options{})
+	// The tracing interceptor is also applied to every Worker created from this Client.
+	temporalClient, err := client.Dial(client.Options{
+		MetricsHandler: workerMetrics.Handler(),
+		Interceptors:   []interceptor.ClientInterceptor{tracingInterceptor},
+		DataConverter:  dataConverter,
+	})
 	if err != nil {
-		log.Fatalln("Unable to create client", err)
+		log.Println("Unable to create client", err)
+		return exitStartFailure
 	}
 	defer temporalClient.Close()
+	// Serve the health endpoints so that an orchestrator can probe the Worker Process.
+	health := yourapp.NewWorkerHealth(temporalClient)
+	go func() {
+		err := http.ListenAndServe(*healthAddr, health.Handler())
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Println("Unable to run health server", err)
+		}
+	}()
 	// Create a new Worker.
-	yourWorker := worker.New(temporalClient, "your-custom-task-queue-name", worker.Options{})
+	// WorkerStopTimeout is how long Stop() waits for running Activities before canceling them.
+	// The logging interceptor writes one record per Workflow run and Activity attempt,
+	// and the Activity tracker tells drain whether every running Activity finished.
+	activityTracker := yourapp.NewActivityTracker()
+	yourWorker := worker.New(temporalClient, "your-custom-task-queue-name", worker.Options{
+		WorkerStopTimeout: *drainTimeout,
+		Interceptors:      []interceptor.WorkerInterceptor{yourapp.NewLoggingInterceptor(), activityTracker},
+	})
 	// Register your Workflow Definitions with the Worker.
 	// Use the ReisterWorkflow or RegisterWorkflowWithOptions method for each Workflow registration.
 	yourWorker.RegisterWorkflow(yourapp.YourWorkflowDefinition)
@@ -58,11 +139,34 @@ func main() {
 		Name: "JustAnotherActivity",
 	}
 	yourWorker.RegisterActivityWithOptions(yourapp.YourSimpleActivityDefinition, registerAOptions)
-	// Run the Worker
-	err = yourWorker.Run(worker.InterruptCh())
+	// Start the Worker and drain it on SIGINT or SIGTERM.
+	err = yourWorker.Start()
 	if err != nil {
-		log.Fatalln("Unable to start Worker", err)
+		log.Println("Unable to start Worker", err)
+		return exitStartFailure
+	}
+	health.MarkStarted()
+	<-worker.InterruptCh()
+	health.MarkDraining()
+	return drain(yourWorker, activityTracker, *drainTimeout)
+}
+
+// drain stops polling and waits up to timeout for running Activities to finish.
+// Stop returns both when they finish and when it cancels them after timeout,
+// so the time Stop took, and the Activities still running or canceled since the drain began, tell the two apart.
+// Activities that their Workflow canceled before timeout don't make the drain time out.
+func drain(w worker.Worker, activities *yourapp.ActivityTracker, timeout time.Duration) int {
+	log.Println("Draining Worker, waiting up to", timeout)
+	canceledBefore := activities.Canceled()
+	start := time.Now()
+	w.Stop()
+	running, canceled := activities.Running(), activities.Canceled()-canceledBefore
+	if time.Since(start) >= timeout && (running > 0 || canceled > 0) {
+		log.Println("Drain timed out:", running, "Activities still running,", canceled, "canceled")
+		return exitDrainTimeout
 	}
+	log.Println("Worker drained,", canceled, "Activities canceled by their Workflow")
+	return exitOK
 }
 
 /*
@@ -99,7 +203,7 @@ tags:
 - go sdk
 - code sample
 - worker
-lines: 1-40, 46-55, 61-66, 78-91
+lines: 1-121, 127-136, 142-170, 182-195
 @dacx */
 
 /* @dacx
@@ -111,7 +215,7 @@ tags:
 - go sdk
 - code sample
 - worker
-lines: 1-12, 28, 37, 41-45, 66-72
+lines: 1-22, 56, 111-118, 122-126, 152, 172-176
 @dacx */
 
 /* @dacx
@@ -123,5 +227,5 @@ tags:
 - go sdk
 - code sample
 - worker
-lines: 28, 37, 56-62, 66, 74-76
+lines: 56, 111-118, 137-143, 152, 178-180
 @dacx */
@@ -59,6 +59,8 @@ func (a *YourActivityObject) YourActivityDefinition(ctx context.Context, param Y
 	logger := activity.GetLogger(ctx)
 	logger.Info("The message is:", param.ActivityParamX)
 	logger.Info("The number is:", param.ActivityParamY)
+	// Count executions with a custom metric; the SDK tags it with the Activity Type.
+	activity.GetMetricsHandler(ctx).Counter(YourActivityExecutionsMetric).Inc(1)
 	// Return data using a Struct so that the function signature is backward compatible.
 	result := &YourActivityResultObject{
 		ResultFieldX: "Success",
@@ -86,17 +88,22 @@ func (a *YourActivityObject) PrintInfo(ctx context.Context, param YourActivityPa
 	logger := activity.GetLogger(ctx)
 	logger.Info("The message is:", param.ActivityParamX)
 	logger.Info("The number is:", param.ActivityParamY)
+	activity.GetMetricsHandler(ctx).Counter(YourActivityExecutionsMetric).Inc(1)
 	return nil
 }
 
 // GetInfo is another custom Activity Definition
 func (a *YourActivityObject) GetInfo(ctx context.Context) (*YourActivityResultObject, error) {
+	activity.GetMetricsHandler(ctx).Counter(YourActivityExecutionsMetric).Inc(1)
 	return &YourActivityResultObject{
 		ResultFieldX: *a.Message,
 		ResultFieldY: *a.Number,
 	}, nil
 }
 
+// YourActivityExecutionsMetric is the name of the counter incremented by each YourActivityObject Activity.
+const YourActivityExecutionsMetric = "your_activity_executions"
+
 /* @dacx
 id: how-to-develop-an-activity-definition-in-go
 title: How to develop an Activity Definition in Go
@@ -106,7 +113,7 @@ tags:
 - go sdk
 - code sample
 - activity
-lines: 1-7, 37-56, 70-82
+lines: 1-7, 37-56, 72-84
 @dacx */
 
 /* @dacx
@@ -118,7 +125,7 @@ tags:
 - go sdk
 - code sample
 - activity
-lines: 9-22, 56, 70
+lines: 9-22, 56, 72
 @dacx */
 
 /* @dacx
@@ -130,5 +137,5 @@ tags:
 - go sdk
 - code sample
 - activity
-lines: 24-35, 56, 63-70
+lines: 24-35, 56, 65-72
 @dacx */
@@ -0,0 +1,131 @@
+package yourupdate
+
+import (
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+)
+
+/*
+Query the counter instead of sending an Update to read it.
+Queries don't change Workflow state and aren't recorded in the Event History.
+
+Every applied Update is also recorded in an audit log in the Workflow.
+The log keeps only the most recent MaxAuditLogEntries entries so that Workflow memory stays bounded,
+and it's read one page at a time so that a single Query result stays small.
+*/
+
+// YourTotalQueryName is the name of the Query that returns the current total.
+const YourTotalQueryName = "your_total_query_name"
+
+// YourAuditLogQueryName is the name of the Query that returns a page of the audit log.
+const YourAuditLogQueryName = "your_audit_log_query_name"
+
+const (
+	// MaxAuditLogEntries is the number of entries kept in the audit log. Older entries are dropped.
+	MaxAuditLogEntries = 1000
+	// DefaultAuditLogPageSize is the page size used when AuditLogPageRequest.PageSize is not set.
+	DefaultAuditLogPageSize = 20
+	// MaxAuditLogPageSize is the largest page that a Query returns.
+	MaxAuditLogPageSize = 100
+)
+
+// AuditEntry records an applied Update.
+type AuditEntry struct {
+	// Sequence numbers the entries of a Workflow run from 1.
+	Sequence int
+	// UpdateID is the Update Id that the Client sent in the Update argument, if any.
+	UpdateID string
+	// Operation is OperationAdd for the add Updates.
+	Operation Operation
+	// Addend is the change that the Update made to the total.
+	Addend int
+	Total  int
+	Time   time.Time
+}
+
+// AuditLogPageRequest defines the structure of the audit log Query argument.
+type AuditLogPageRequest struct {
+	// PageToken is the NextPageToken of the previous page. Leave it empty for the first page.
+	PageToken int
+	PageSize  int
+}
+
+// AuditLogPage defines the structure of the audit log Query result.
+type AuditLogPage struct {
+	Entries []AuditEntry
+	// NextPageToken is 0 when there are no more entries.
+	NextPageToken int
+	// Dropped is the number of entries dropped from the log because of its size limit.
+	Dropped int
+}
+
+// auditLog keeps the most recent entries, oldest first.
+type auditLog struct {
+	entries    []AuditEntry
+	maxEntries int
+	sequence   int
+}
+
+func newAuditLog(maxEntries int) *auditLog {
+	return &auditLog{maxEntries: maxEntries}
+}
+
+// add records an applied Update. It sets the Sequence and Time of entry.
+func (a *auditLog) add(ctx workflow.Context, entry AuditEntry) {
+	a.sequence++
+	if len(a.entries) == a.maxEntries {
+		copy(a.entries, a.entries[1:])
+		a.entries = a.entries[:len(a.entries)-1]
+	}
+	entry.Sequence = a.sequence
+	entry.Time = workflow.Now(ctx)
+	a.entries = append(a.entries, entry)
+}
+
+// page returns the entries from request.PageToken on.
+// If those entries were dropped, the page starts at the oldest kept entry.
+func (a *auditLog) page(request AuditLogPageRequest) (AuditLogPage, error) {
+	if request.PageToken < 0 {
+		return AuditLogPage{}, fmt.Errorf("invalid page token %d", request.PageToken)
+	}
+	pageSize := request.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultAuditLogPageSize
+	}
+	if pageSize > MaxAuditLogPageSize {
+		pageSize = MaxAuditLogPageSize
+	}
+	result := AuditLogPage{Dropped: a.sequence - len(a.entries)}
+	if len(a.entries) == 0 {
+		return result, nil
+	}
+	start := request.PageToken - a.entries[0].Sequence
+	if start < 0 {
+		start = 0
+	}
+	if start >= len(a.entries) {
+		return result, nil
+	}
+	end := start + pageSize
+	if end < len(a.entries) {
+		result.NextPageToken = a.entries[end].Sequence
+	} else {
+		end = len(a.entries)
+	}
+	// Copy so that the result doesn't share memory with the log.
+	result.Entries = append([]AuditEntry(nil), a.entries[start:end]...)
+	return result, nil
+}
+
+// setQueryHandlers registers the total and audit log Queries.
+func setQueryHandlers(ctx workflow.Context, counter *int, audit *auditLog) error {
+	err := workflow.SetQueryHandler(ctx, YourTotalQueryName, func() (int, error) {
+		return *counter, nil
+	})
+	if err != nil {
+		return err
+	}
+	return workflow.SetQueryHandler(ctx, YourAuditLogQueryName, audit.page)
+}
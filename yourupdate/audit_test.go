@@ -0,0 +1,87 @@
+package yourupdate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+func Test_UpdatableWorkflow_Queries(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	start := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	env.SetStartTime(start)
+	for i, addend := range []int{2, 3, 4} {
+		addend := addend
+		updateID := []string{"update-1", "update-2", "update-3"}[i]
+		env.RegisterDelayedCallback(func() {
+			env.UpdateWorkflow(YourUpdateName, &updateCallbacks{}, YourUpdateArg{Add: addend, UpdateID: updateID})
+		}, time.Duration(i+1)*time.Second)
+	}
+	env.RegisterDelayedCallback(func() {
+		value, err := env.QueryWorkflow(YourTotalQueryName)
+		require.NoError(t, err)
+		var total int
+		require.NoError(t, value.Get(&total))
+		require.Equal(t, 10, total)
+
+		value, err = env.QueryWorkflow(YourAuditLogQueryName, AuditLogPageRequest{PageSize: 2})
+		require.NoError(t, err)
+		var page AuditLogPage
+		require.NoError(t, value.Get(&page))
+		require.Equal(t, []AuditEntry{
+			{Sequence: 1, UpdateID: "update-1", Operation: OperationAdd, Addend: 2, Total: 3, Time: start.Add(time.Second)},
+			{Sequence: 2, UpdateID: "update-2", Operation: OperationAdd, Addend: 3, Total: 6, Time: start.Add(2 * time.Second)},
+		}, page.Entries)
+		require.Equal(t, 3, page.NextPageToken)
+
+		value, err = env.QueryWorkflow(YourAuditLogQueryName, AuditLogPageRequest{PageToken: page.NextPageToken, PageSize: 2})
+		require.NoError(t, err)
+		require.NoError(t, value.Get(&page))
+		require.Len(t, page.Entries, 1)
+		require.Equal(t, 10, page.Entries[0].Total)
+		require.Zero(t, page.NextPageToken)
+
+		env.SignalWorkflow(YourCloseSignalName, nil)
+	}, 4*time.Second)
+
+	env.ExecuteWorkflow(YourUpdatableWorkflow, WFParam{StartCount: 1})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+}
+
+func Test_auditLog_Cap(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	audit := newAuditLog(3)
+	env.ExecuteWorkflow(func(ctx workflow.Context) error {
+		for i := 1; i <= 5; i++ {
+			audit.add(ctx, AuditEntry{Operation: OperationAdd, Addend: 1, Total: i})
+		}
+		return nil
+	})
+	require.NoError(t, env.GetWorkflowError())
+
+	page, err := audit.page(AuditLogPageRequest{})
+	require.NoError(t, err)
+	require.Equal(t, 2, page.Dropped)
+	require.Len(t, page.Entries, 3)
+	require.Equal(t, 3, page.Entries[0].Sequence)
+
+	// A token for a dropped entry starts at the oldest kept entry.
+	page, err = audit.page(AuditLogPageRequest{PageToken: 1, PageSize: 1})
+	require.NoError(t, err)
+	require.Equal(t, 3, page.Entries[0].Sequence)
+	require.Equal(t, 4, page.NextPageToken)
+
+	// Past the end.
+	page, err = audit.page(AuditLogPageRequest{PageToken: 6})
+	require.NoError(t, err)
+	require.Empty(t, page.Entries)
+
+	_, err = audit.page(AuditLogPageRequest{PageToken: -1})
+	require.Error(t, err)
+}
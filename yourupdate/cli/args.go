@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// parseArg builds the Update argument from either a JSON document or a list of
+// key=value pairs. It returns nil when neither is given.
+// A value in a key=value pair is read as JSON when it is valid JSON, so
+// Add=5 sends a number and Operation=add sends a string.
+func parseArg(input string, pairs []string) (interface{}, error) {
+	if input != "" && len(pairs) > 0 {
+		return nil, fmt.Errorf("use either -input or key=value arguments, not both")
+	}
+	if input != "" {
+		var arg interface{}
+		err := json.Unmarshal([]byte(input), &arg)
+		if err != nil {
+			return nil, fmt.Errorf("-input is not valid JSON: %w", err)
+		}
+		return arg, nil
+	}
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	arg := make(map[string]interface{}, len(pairs))
+	for _, pair := range pairs {
+		key, raw, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("argument %q is not a key=value pair", pair)
+		}
+		if _, ok := arg[key]; ok {
+			return nil, fmt.Errorf("key %q is given more than once", key)
+		}
+		var value interface{}
+		if json.Unmarshal([]byte(raw), &value) != nil {
+			value = raw
+		}
+		arg[key] = value
+	}
+	return arg, nil
+}
+
+// withUpdateID sets the UpdateID field of an object argument, unless it is
+// already set, so the Workflow can record which request it applied.
+func withUpdateID(arg interface{}, updateID string) interface{} {
+	object, ok := arg.(map[string]interface{})
+	if !ok {
+		return arg
+	}
+	if _, ok := object["UpdateID"]; !ok {
+		object["UpdateID"] = updateID
+	}
+	return object
+}
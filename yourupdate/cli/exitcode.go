@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"go.temporal.io/api/serviceerror"
+
+	"documentation-samples-go/yourupdate/validate"
+)
+
+// Exit codes of the CLI.
+const (
+	exitOK = iota
+	// The Update was accepted but its handler returned an error, even one
+	// that validate.NotApplied wrapped, or the request failed for a reason not listed below.
+	exitFailed
+	// The command line is invalid.
+	exitUsage
+	// A validator rejected the Update. Validators reject only with a validate.Code,
+	// and the errors of accepted Updates carry none.
+	exitRejected
+	// The Workflow Execution does not exist or has already closed.
+	exitNotFound
+	// The Temporal Service could not be reached or did not answer in time.
+	exitTransport
+)
+
+// exitCode maps an error returned while sending an Update to an exit code.
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	if _, ok := validate.CodeOf(err); ok {
+		return exitRejected
+	}
+	var notFound *serviceerror.NotFound
+	if errors.As(err, &notFound) {
+		return exitNotFound
+	}
+	var unavailable *serviceerror.Unavailable
+	var deadlineExceeded *serviceerror.DeadlineExceeded
+	var canceled *serviceerror.Canceled
+	if errors.As(err, &unavailable) || errors.As(err, &deadlineExceeded) || errors.As(err, &canceled) ||
+		errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) || errors.Is(err, errDial) {
+		return exitTransport
+	}
+	return exitFailed
+}
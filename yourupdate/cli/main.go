@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	updatepb "go.temporal.io/api/update/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/temporal"
+
+	"documentation-samples-go/codec"
+	"documentation-samples-go/yourupdate"
+	"documentation-samples-go/yourupdate/validate"
+)
+
+const (
+	// maxAttempts is how many times an Update refused with RETRY_LATER is sent.
+	maxAttempts = 5
+	retryDelay  = time.Second
+)
+
+// errDial marks failures to connect to the Temporal Service.
+var errDial = errors.New("unable to create client")
+
+type options struct {
+	address    string
+	namespace  string
+	workflowID string
+	runID      string
+	name       string
+	input      string
+	wait       string
+	updateID   string
+
+	start        bool
+	workflowType string
+	taskQueue    string
+	startCount   int
+}
+
+var waitStages = map[string]enumspb.UpdateWorkflowExecutionLifecycleStage{
+	"accepted":  enumspb.UPDATE_WORKFLOW_EXECUTION_LIFECYCLE_STAGE_ACCEPTED,
+	"completed": enumspb.UPDATE_WORKFLOW_EXECUTION_LIFECYCLE_STAGE_COMPLETED,
+}
+
+func main() {
+	var opts options
+	flag.StringVar(&opts.address, "address", client.DefaultHostPort, "Temporal Service address")
+	flag.StringVar(&opts.namespace, "namespace", client.DefaultNamespace, "Namespace of the Workflow")
+	flag.StringVar(&opts.workflowID, "workflow-id", yourupdate.YourUpdateWFID, "Workflow Id to update")
+	flag.StringVar(&opts.runID, "run-id", "", "Run Id to update; empty means the current run")
+	flag.StringVar(&opts.name, "name", yourupdate.YourUpdateName, "Update name")
+	flag.StringVar(&opts.input, "input", "", "Update argument as a JSON document, instead of key=value arguments")
+	flag.StringVar(&opts.wait, "wait", "completed", "Lifecycle stage to wait for: accepted or completed")
+	flag.StringVar(&opts.updateID, "update-id", "", "Update Id; requests with the same Id are applied once (default: a new UUID)")
+	flag.BoolVar(&opts.start, "start", false, "Start the Workflow if it is not running, then send the Update")
+	flag.StringVar(&opts.workflowType, "workflow-type", "YourUpdatableWorkflow", "Workflow type to start with -start")
+	flag.StringVar(&opts.taskQueue, "task-queue", yourupdate.TaskQueueName, "Task Queue of the Workflow started with -start")
+	flag.IntVar(&opts.startCount, "start-count", 0, "StartCount of the Workflow started with -start")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [key=value ...]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	arg, err := parseArg(opts.input, flag.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitUsage)
+	}
+	if _, ok := waitStages[opts.wait]; !ok {
+		fmt.Fprintf(os.Stderr, "-wait must be accepted or completed, not %q\n", opts.wait)
+		os.Exit(exitUsage)
+	}
+	if opts.start && opts.runID != "" {
+		fmt.Fprintln(os.Stderr, "-start can't be used with -run-id")
+		os.Exit(exitUsage)
+	}
+	if opts.updateID == "" {
+		opts.updateID = uuid.NewString()
+	}
+	err = run(context.Background(), opts, withUpdateID(arg, opts.updateID))
+	if err != nil {
+		log.Println("Update Id", opts.updateID, "failed:", err)
+	}
+	os.Exit(exitCode(err))
+}
+
+func run(ctx context.Context, opts options, arg interface{}) error {
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		return err
+	}
+	temporalClient, err := client.Dial(client.Options{
+		HostPort:      opts.address,
+		Namespace:     opts.namespace,
+		DataConverter: dataConverter,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %v", errDial, err)
+	}
+	defer temporalClient.Close()
+
+	var args []interface{}
+	if arg != nil {
+		args = append(args, arg)
+	}
+	payloads, err := dataConverter.ToPayloads(args...)
+	if err != nil {
+		return err
+	}
+	// A Workflow that is continuing as new refuses Updates with RETRY_LATER
+	// without applying them. Send the Update again so that it reaches the new run.
+	for attempt := 1; ; attempt++ {
+		if opts.start {
+			startOptions := client.StartWorkflowOptions{
+				ID:        opts.workflowID,
+				TaskQueue: opts.taskQueue,
+			}
+			err = yourupdate.WithStart(ctx, temporalClient, startOptions, opts.workflowType, yourupdate.WFParam{StartCount: opts.startCount}, func(ctx context.Context) error {
+				return send(ctx, temporalClient, dataConverter, opts, payloads)
+			})
+		} else {
+			err = send(ctx, temporalClient, dataConverter, opts, payloads)
+		}
+		if !validate.RetryLater(err) || opts.runID != "" || attempt == maxAttempts {
+			return err
+		}
+		log.Println("Workflow is not taking Updates right now, retrying:", err)
+		time.Sleep(retryDelay)
+	}
+}
+
+// send sends the Update once and waits for the requested lifecycle stage.
+func send(ctx context.Context, temporalClient client.Client, dataConverter converter.DataConverter, opts options, payloads *commonpb.Payloads) error {
+	// Call the service directly so that a rejection is reported even when
+	// only waiting for the Update to be accepted.
+	response, err := temporalClient.WorkflowService().UpdateWorkflowExecution(ctx, &workflowservice.UpdateWorkflowExecutionRequest{
+		Namespace: opts.namespace,
+		WorkflowExecution: &commonpb.WorkflowExecution{
+			WorkflowId: opts.workflowID,
+			RunId:      opts.runID,
+		},
+		WaitPolicy: &updatepb.WaitPolicy{LifecycleStage: waitStages[opts.wait]},
+		Request: &updatepb.Request{
+			Meta:  &updatepb.Meta{UpdateId: opts.updateID},
+			Input: &updatepb.Input{Name: opts.name, Args: payloads},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	var result interface{}
+	switch outcome := response.GetOutcome().GetValue().(type) {
+	case *updatepb.Outcome_Failure:
+		failureConverter := temporal.NewDefaultFailureConverter(temporal.DefaultFailureConverterOptions{
+			DataConverter: dataConverter,
+		})
+		return failureConverter.FailureToError(outcome.Failure)
+	case *updatepb.Outcome_Success:
+		err = dataConverter.FromPayloads(outcome.Success, &result)
+		if err != nil {
+			return err
+		}
+	default:
+		if opts.wait == "accepted" {
+			log.Println("Update accepted, Update Id", opts.updateID)
+			return nil
+		}
+		// The service returned before the Update completed, so poll for it.
+		handle := temporalClient.GetWorkflowUpdateHandle(client.GetWorkflowUpdateHandleOptions{
+			WorkflowID: opts.workflowID,
+			RunID:      response.GetUpdateRef().GetWorkflowExecution().GetRunId(),
+			UpdateID:   opts.updateID,
+		})
+		err = handle.Get(ctx, &result)
+		if err != nil {
+			return err
+		}
+	}
+	output, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	log.Println("Update succeeded, Update Id", opts.updateID)
+	fmt.Println(string(output))
+	return nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/temporal"
+
+	"documentation-samples-go/yourupdate"
+	"documentation-samples-go/yourupdate/validate"
+)
+
+func Test_ParseArg(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		pairs   []string
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "none", want: nil},
+		{name: "json", input: `{"Add": 5}`, want: map[string]interface{}{"Add": 5.0}},
+		{name: "json scalar", input: `5`, want: 5.0},
+		{name: "invalid json", input: `{"Add":`, wantErr: true},
+		{
+			name:  "pairs",
+			pairs: []string{"Operation=multiply", "Operand=3", "Quoted=\"3\""},
+			want:  map[string]interface{}{"Operation": "multiply", "Operand": 3.0, "Quoted": "3"},
+		},
+		{name: "empty value", pairs: []string{"UpdateID="}, want: map[string]interface{}{"UpdateID": ""}},
+		{name: "missing equals", pairs: []string{"Add"}, wantErr: true},
+		{name: "missing key", pairs: []string{"=5"}, wantErr: true},
+		{name: "duplicate key", pairs: []string{"Add=1", "Add=2"}, wantErr: true},
+		{name: "both", input: `{}`, pairs: []string{"Add=1"}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseArg(test.input, test.pairs)
+			if test.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.want, got)
+		})
+	}
+}
+
+func Test_WithUpdateID(t *testing.T) {
+	require.Equal(t, map[string]interface{}{"Add": 1.0, "UpdateID": "id"},
+		withUpdateID(map[string]interface{}{"Add": 1.0}, "id"))
+	require.Equal(t, map[string]interface{}{"UpdateID": "mine"},
+		withUpdateID(map[string]interface{}{"UpdateID": "mine"}, "id"))
+	require.Equal(t, 5.0, withUpdateID(5.0, "id"))
+	require.Nil(t, withUpdateID(nil, "id"))
+}
+
+// overTheWire returns err as a Client receives it: converted to a Failure and back.
+func overTheWire(err error) error {
+	failureConverter := temporal.GetDefaultFailureConverter()
+	return failureConverter.FailureToError(failureConverter.ErrorToFailure(err))
+}
+
+func Test_ExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "success", err: nil, want: exitOK},
+		{name: "rejected", err: validate.Reject(validate.CodeOutOfRange, "too big"), want: exitRejected},
+		{name: "rejected while closing", err: overTheWire(yourupdate.ErrClosing), want: exitRejected},
+		{name: "handler error", err: temporal.NewApplicationError("boom", "SomeError"), want: exitFailed},
+		{name: "accepted but not applied", err: overTheWire(validate.NotApplied(yourupdate.ErrClosing)), want: exitFailed},
+		{name: "not found", err: serviceerror.NewNotFound("workflow not found"), want: exitNotFound},
+		{name: "unavailable", err: serviceerror.NewUnavailable("connection refused"), want: exitTransport},
+		{name: "deadline", err: serviceerror.NewDeadlineExceeded("timeout"), want: exitTransport},
+		{name: "context deadline", err: fmt.Errorf("poll: %w", context.DeadlineExceeded), want: exitTransport},
+		{name: "dial", err: fmt.Errorf("%w: refused", errDial), want: exitTransport},
+		{name: "other", err: errors.New("something else"), want: exitFailed},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.want, exitCode(test.err))
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"go.temporal.io/sdk/client"
+
+	"documentation-samples-go/codec"
+	"documentation-samples-go/yourupdate"
+)
+
+func main() {
+	// Close the YourUpdatableWorkflow execution unless another Workflow Id is given.
+	workflowID := yourupdate.YourUpdateWFID
+	if len(os.Args) == 2 {
+		workflowID = os.Args[1]
+	}
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		log.Fatalln("Unable to create Data Converter", err)
+	}
+	temporalClient, err := client.Dial(client.Options{
+		HostPort:      client.DefaultHostPort,
+		DataConverter: dataConverter,
+	})
+	if err != nil {
+		log.Fatalln("Unable to create client", err)
+	}
+	defer temporalClient.Close()
+	err = temporalClient.SignalWorkflow(context.Background(), workflowID, "", yourupdate.YourCloseSignalName, nil)
+	if err != nil {
+		log.Fatalln("Unable to signal workflow", err)
+	}
+	// Wait for the Workflow to finish its running Updates and return.
+	var result yourupdate.WFResult
+	err = temporalClient.GetWorkflow(context.Background(), workflowID, "").Get(context.Background(), &result)
+	if err != nil {
+		log.Fatalln("Workflow failed", err)
+	}
+	log.Println("Workflow closed, end total: ", result.EndTotal)
+}
@@ -0,0 +1,182 @@
+package yourupdate
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+
+	"documentation-samples-go/yourupdate/validate"
+)
+
+/*
+An updatable Workflow should not stop while Updates are still coming in, and should not run forever either.
+The Workflows in this package finish when either of the following happens:
+
+- A Client sends the close Signal.
+- No Update has been accepted for the idle timeout. Each accepted Update restarts the timeout.
+
+Before returning, the Workflow waits for the Update handlers that are still running.
+Updates that arrive after the Workflow starts closing are rejected with ErrClosing.
+Validators reject them before they are written to the Event History.
+A handler that finds the Workflow closing, because it has no validator or the close Signal arrived after validation,
+fails the accepted Update with validate.NotApplied, so that Clients don't take it for a rejection.
+
+To keep the Event History bounded, a run continues as new with the current total
+once it has accepted WFParam.MaxUpdatesPerRun Updates or its history reaches WFParam.MaxHistoryLength Events.
+Updates that arrive during the handoff are rejected with ErrContinuingAsNew before they are applied,
+so the Client can send them again to the new run.
+
+Runs that started before this lifecycle existed slept for 60 seconds and then finished.
+They keep doing so when replayed, through workflow.GetVersion with LifecycleChangeID.
+*/
+
+// YourCloseSignalName is the name of the Signal that asks an updatable Workflow to finish.
+const YourCloseSignalName = "your_close_signal_name"
+
+// DefaultIdleTimeout is the idle timeout used when WFParam.IdleTimeout is not set.
+const DefaultIdleTimeout = 60 * time.Second
+
+// DefaultMaxUpdatesPerRun is the number of Updates after which a run continues as new
+// when WFParam.MaxUpdatesPerRun is not set.
+const DefaultMaxUpdatesPerRun = 1000
+
+// DefaultMaxHistoryLength is the number of history Events after which a run continues as new
+// when WFParam.MaxHistoryLength is not set.
+const DefaultMaxHistoryLength = 10000
+
+// LifecycleChangeID is the workflow.GetVersion change Id of the close Signal, the idle timeout and continue-as-new.
+const LifecycleChangeID = "close-on-signal-or-idle"
+
+// legacySleep is how long runs that started before LifecycleChangeID wait for Updates.
+const legacySleep = 60 * time.Second
+
+// ErrClosing is returned for Updates that arrive after the Workflow started closing.
+// Its code is validate.CodeClosing.
+var ErrClosing = validate.Reject(validate.CodeClosing, "workflow is closing and no longer accepts updates")
+
+// ErrContinuingAsNew is returned for Updates that arrive while the run hands over to a new run.
+// Its code is validate.CodeRetryLater: the Update was not applied, and sending it again reaches the new run.
+var ErrContinuingAsNew = validate.Reject(validate.CodeRetryLater, "workflow is continuing as new, send the update again")
+
+// lifecycle tracks the Updates of a Workflow run so that it knows when it can finish.
+type lifecycle struct {
+	closing    bool
+	continuing bool
+	accepted   int
+	inFlight   int
+	// legacy is set on runs that started before LifecycleChangeID. They never continue as new.
+	legacy bool
+
+	maxUpdates       int
+	maxHistoryLength int
+}
+
+// newLifecycle starts listening for the close Signal.
+func newLifecycle(ctx workflow.Context, param WFParam) *lifecycle {
+	l := &lifecycle{
+		maxUpdates:       param.MaxUpdatesPerRun,
+		maxHistoryLength: param.MaxHistoryLength,
+	}
+	if l.maxUpdates <= 0 {
+		l.maxUpdates = DefaultMaxUpdatesPerRun
+	}
+	if l.maxHistoryLength <= 0 {
+		l.maxHistoryLength = DefaultMaxHistoryLength
+	}
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		workflow.GetSignalChannel(ctx, YourCloseSignalName).Receive(ctx, nil)
+		workflow.GetLogger(ctx).Info("Received close signal")
+		l.closing = true
+	})
+	return l
+}
+
+// begin is called at the start of an Update handler.
+// Call end when the handler returns.
+// The Update that reaches the per-run limit is applied, and the ones after it are refused
+// with the error of validate wrapped by validate.NotApplied, since the handler runs after the Update was accepted.
+func (l *lifecycle) begin() error {
+	err := l.validate()
+	if err != nil {
+		return validate.NotApplied(err)
+	}
+	l.accepted++
+	l.inFlight++
+	if l.accepted >= l.maxUpdates && !l.legacy {
+		l.continuing = true
+	}
+	return nil
+}
+
+// end is called when an Update handler returns.
+func (l *lifecycle) end() {
+	l.inFlight--
+}
+
+// validate rejects Updates once the Workflow is closing or continuing as new.
+// Use it in validators so that late Updates are not written to the Event History.
+func (l *lifecycle) validate() error {
+	if l.closing {
+		return ErrClosing
+	}
+	if l.continuing {
+		return ErrContinuingAsNew
+	}
+	return nil
+}
+
+// wait blocks until the close Signal arrives, no Update is accepted for idleTimeout,
+// or the run reaches its limits, and then until every running Update handler has returned.
+// On runs that started before LifecycleChangeID, it sleeps for 60 seconds instead.
+func (l *lifecycle) wait(ctx workflow.Context, idleTimeout time.Duration) error {
+	version := workflow.GetVersion(ctx, LifecycleChangeID, workflow.DefaultVersion, 1)
+	if version == workflow.DefaultVersion {
+		l.legacy = true
+		// These runs ignored the error of the sleep, so keep ignoring it.
+		_ = workflow.Sleep(ctx, legacySleep)
+		return nil
+	}
+	if idleTimeout <= 0 {
+		idleTimeout = DefaultIdleTimeout
+	}
+	for !l.closing && !l.continuing {
+		accepted := l.accepted
+		ok, err := workflow.AwaitWithTimeout(ctx, idleTimeout, func() bool {
+			return l.closing || l.continuing || l.accepted != accepted
+		})
+		if err != nil {
+			return err
+		}
+		if !ok {
+			workflow.GetLogger(ctx).Info("No updates received, closing", "IdleTimeout", idleTimeout)
+			l.closing = true
+		} else if length := workflow.GetInfo(ctx).GetCurrentHistoryLength(); length >= l.maxHistoryLength {
+			workflow.GetLogger(ctx).Info("History limit reached", "HistoryLength", length)
+			l.continuing = true
+		}
+	}
+	err := workflow.Await(ctx, func() bool {
+		return l.inFlight == 0
+	})
+	if err != nil {
+		return err
+	}
+	// A close Signal delivered in this Workflow Task may not have reached the
+	// Signal goroutine yet. Take it here so that it is not lost with the run.
+	if l.continuing && !l.closing && workflow.GetSignalChannel(ctx, YourCloseSignalName).ReceiveAsync(nil) {
+		workflow.GetLogger(ctx).Info("Received close signal")
+		l.closing = true
+	}
+	return nil
+}
+
+// continueAsNew returns the error that continues workflowFn as new with the current total
+// when wait returned because the run reached its limits. Otherwise it returns nil.
+func (l *lifecycle) continueAsNew(ctx workflow.Context, workflowFn interface{}, param WFParam, counter int) error {
+	if l.legacy || l.closing || !l.continuing {
+		return nil
+	}
+	workflow.GetLogger(ctx).Info("Continuing as new", "Updates", l.accepted, "Total", counter)
+	param.StartCount = counter
+	return workflow.NewContinueAsNewError(ctx, workflowFn, param)
+}
@@ -0,0 +1,300 @@
+package yourupdate
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	historypb "go.temporal.io/api/history/v1"
+	taskqueuepb "go.temporal.io/api/taskqueue/v1"
+	updatepb "go.temporal.io/api/update/v1"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+
+	"documentation-samples-go/yourupdate/validate"
+)
+
+func Test_UpdatableWorkflow_Close(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	start := env.Now()
+	first := &updateCallbacks{}
+	env.RegisterDelayedCallback(func() {
+		env.UpdateWorkflow(YourUpdateName, first, YourUpdateArg{Add: 5})
+	}, time.Second)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(YourCloseSignalName, nil)
+	}, 2*time.Second)
+
+	env.ExecuteWorkflow(YourUpdatableWorkflow, WFParam{StartCount: 1, IdleTimeout: time.Hour})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.NoError(t, first.err)
+	require.Equal(t, YourUpdateResult{Total: 6}, first.result)
+	var result WFResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, 6, result.EndTotal)
+	// Closed by the Signal, long before the idle timeout.
+	require.Equal(t, 2*time.Second, env.Now().Sub(start))
+}
+
+func Test_UpdatableWorkflow_IdleTimeoutResetsOnUpdate(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	start := env.Now()
+	for _, delay := range []time.Duration{50 * time.Second, 100 * time.Second} {
+		env.RegisterDelayedCallback(func() {
+			env.UpdateWorkflow(YourUpdateName, &updateCallbacks{}, YourUpdateArg{Add: 1})
+		}, delay)
+	}
+
+	env.ExecuteWorkflow(YourUpdatableWorkflow, WFParam{})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var result WFResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, 2, result.EndTotal)
+	// The last Update at 100s restarted the default 60s idle timeout.
+	require.Equal(t, 160*time.Second, env.Now().Sub(start))
+}
+
+func Test_UpdatableWorkflowWithValidator_Close(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	negative := &updateCallbacks{}
+	env.RegisterDelayedCallback(func() {
+		env.UpdateWorkflow(YourValidatedUpdateName, negative, YourUpdateArg{Add: -1})
+		env.SignalWorkflow(YourCloseSignalName, nil)
+	}, time.Second)
+
+	env.ExecuteWorkflow(UpdatableWorkflowWithValidator, WFParam{StartCount: 3})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.False(t, negative.accepted)
+	code, ok := validate.CodeOf(negative.rejected)
+	require.True(t, ok)
+	require.Equal(t, validate.CodeOutOfRange, code)
+	var result WFResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, 3, result.EndTotal)
+}
+
+func Test_lifecycle_RejectsUpdatesWhenClosing(t *testing.T) {
+	l := &lifecycle{closing: true}
+	require.ErrorIs(t, l.validate(), ErrClosing)
+	require.ErrorIs(t, l.begin(), ErrClosing)
+	require.Zero(t, l.inFlight)
+}
+
+func Test_lifecycle_ContinuesAfterMaxUpdates(t *testing.T) {
+	l := &lifecycle{maxUpdates: 2, maxHistoryLength: DefaultMaxHistoryLength}
+	require.NoError(t, l.begin())
+	require.False(t, l.continuing)
+	require.NoError(t, l.begin())
+	require.True(t, l.continuing)
+	require.ErrorIs(t, l.validate(), ErrContinuingAsNew)
+	require.ErrorIs(t, l.begin(), ErrContinuingAsNew)
+	require.Equal(t, 2, l.accepted)
+	require.Equal(t, 2, l.inFlight)
+}
+
+// continuedParam returns the WFParam that the Workflow continued as new with.
+func continuedParam(t *testing.T, env *testsuite.TestWorkflowEnvironment) WFParam {
+	t.Helper()
+	var canErr *workflow.ContinueAsNewError
+	require.True(t, errors.As(env.GetWorkflowError(), &canErr), "expected continue-as-new, got %v", env.GetWorkflowError())
+	var param WFParam
+	require.NoError(t, converter.GetDefaultDataConverter().FromPayloads(canErr.Input, &param))
+	return param
+}
+
+func Test_UpdatableWorkflow_ContinueAsNewStraddlingUpdates(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	// Five Updates arrive together; the run only takes three of them.
+	updates := make([]*updateCallbacks, 5)
+	env.RegisterDelayedCallback(func() {
+		for i := range updates {
+			updates[i] = &updateCallbacks{}
+			env.UpdateWorkflow(YourUpdateName, updates[i], YourUpdateArg{Add: i + 1})
+		}
+	}, time.Second)
+
+	param := WFParam{StartCount: 10, IdleTimeout: time.Hour, MaxUpdatesPerRun: 3}
+	env.ExecuteWorkflow(YourUpdatableWorkflow, param)
+	require.True(t, env.IsWorkflowCompleted())
+	for i, total := range []int{11, 13, 16} {
+		require.NoError(t, updates[i].err)
+		require.Equal(t, YourUpdateResult{Total: total}, updates[i].result)
+	}
+	var refused []YourUpdateArg
+	for i, update := range updates[3:] {
+		// Without a validator the Updates were accepted, and their handlers did not apply them.
+		require.True(t, update.accepted)
+		_, ok := validate.CodeOf(update.err)
+		require.False(t, ok)
+		require.True(t, validate.RetryLater(update.err))
+		refused = append(refused, YourUpdateArg{Add: i + 4})
+	}
+	next := continuedParam(t, env)
+	require.Equal(t, WFParam{StartCount: 16, IdleTimeout: time.Hour, MaxUpdatesPerRun: 3}, next)
+
+	// The refused Updates are sent again and reach the new run.
+	env = testSuite.NewTestWorkflowEnvironment()
+	env.RegisterDelayedCallback(func() {
+		for _, arg := range refused {
+			env.UpdateWorkflow(YourUpdateName, &updateCallbacks{}, arg)
+		}
+		env.SignalWorkflow(YourCloseSignalName, nil)
+	}, time.Second)
+	env.ExecuteWorkflow(YourUpdatableWorkflow, next)
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var result WFResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	// No Update was lost or applied twice across the two runs.
+	require.Equal(t, 10+1+2+3+4+5, result.EndTotal)
+}
+
+func Test_UpdatableWorkflowWithValidator_ContinueAsNewRejectsDuringHandoff(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	first, second := &updateCallbacks{}, &updateCallbacks{}
+	env.RegisterDelayedCallback(func() {
+		env.UpdateWorkflow(YourValidatedUpdateName, first, YourUpdateArg{Add: 2})
+		env.UpdateWorkflow(YourValidatedUpdateName, second, YourUpdateArg{Add: 3})
+	}, time.Second)
+
+	env.ExecuteWorkflow(UpdatableWorkflowWithValidator, WFParam{MaxUpdatesPerRun: 1})
+	require.True(t, env.IsWorkflowCompleted())
+	require.True(t, first.accepted)
+	require.Equal(t, YourUpdateResult{Total: 2}, first.result)
+	// The validator refuses the second Update before it is recorded.
+	require.False(t, second.accepted)
+	code, ok := validate.CodeOf(second.rejected)
+	require.True(t, ok)
+	require.Equal(t, validate.CodeRetryLater, code)
+	require.Equal(t, 2, continuedParam(t, env).StartCount)
+}
+
+func Test_UpdatableWorkflow_ContinueAsNewOnHistoryLength(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.SetCurrentHistoryLength(50)
+	env.RegisterDelayedCallback(func() {
+		env.UpdateWorkflow(YourUpdateName, &updateCallbacks{}, YourUpdateArg{Add: 4})
+	}, time.Second)
+
+	env.ExecuteWorkflow(YourUpdatableWorkflow, WFParam{StartCount: 1, MaxHistoryLength: 50})
+	require.True(t, env.IsWorkflowCompleted())
+	require.Equal(t, WFParam{StartCount: 5, MaxHistoryLength: 50}, continuedParam(t, env))
+}
+
+// legacyHistory returns the Event History of a run of workflowType from before LifecycleChangeID:
+// it started at 0, set a 60 second timer, accepted the Update updateName with Add 5 while the timer ran,
+// and completed with 5 when the timer fired.
+func legacyHistory(t *testing.T, workflowType, updateName string) *historypb.History {
+	dataConverter := converter.GetDefaultDataConverter()
+	input, err := dataConverter.ToPayloads(WFParam{})
+	require.NoError(t, err)
+	updateArgs, err := dataConverter.ToPayloads(YourUpdateArg{Add: 5})
+	require.NoError(t, err)
+	updateResult, err := dataConverter.ToPayloads(YourUpdateResult{Total: 5})
+	require.NoError(t, err)
+	result, err := dataConverter.ToPayloads(WFResult{EndTotal: 5})
+	require.NoError(t, err)
+	timeout := legacySleep
+	updateMeta := &updatepb.Meta{UpdateId: "update-1"}
+
+	events := []*historypb.HistoryEvent{
+		{EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_STARTED, Attributes: &historypb.HistoryEvent_WorkflowExecutionStartedEventAttributes{
+			WorkflowExecutionStartedEventAttributes: &historypb.WorkflowExecutionStartedEventAttributes{
+				WorkflowType: &commonpb.WorkflowType{Name: workflowType},
+				TaskQueue:    &taskqueuepb.TaskQueue{Name: TaskQueueName},
+				Input:        input,
+			},
+		}},
+		workflowTaskEvent(enumspb.EVENT_TYPE_WORKFLOW_TASK_SCHEDULED),
+		workflowTaskEvent(enumspb.EVENT_TYPE_WORKFLOW_TASK_STARTED),
+		workflowTaskEvent(enumspb.EVENT_TYPE_WORKFLOW_TASK_COMPLETED),
+		{EventType: enumspb.EVENT_TYPE_TIMER_STARTED, Attributes: &historypb.HistoryEvent_TimerStartedEventAttributes{
+			TimerStartedEventAttributes: &historypb.TimerStartedEventAttributes{TimerId: "5", StartToFireTimeout: &timeout, WorkflowTaskCompletedEventId: 4},
+		}},
+		workflowTaskEvent(enumspb.EVENT_TYPE_WORKFLOW_TASK_SCHEDULED),
+		workflowTaskEvent(enumspb.EVENT_TYPE_WORKFLOW_TASK_STARTED),
+		workflowTaskEvent(enumspb.EVENT_TYPE_WORKFLOW_TASK_COMPLETED),
+		{EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_UPDATE_ACCEPTED, Attributes: &historypb.HistoryEvent_WorkflowExecutionUpdateAcceptedEventAttributes{
+			WorkflowExecutionUpdateAcceptedEventAttributes: &historypb.WorkflowExecutionUpdateAcceptedEventAttributes{
+				ProtocolInstanceId:               updateMeta.UpdateId,
+				AcceptedRequestMessageId:         updateMeta.UpdateId + "/request",
+				AcceptedRequestSequencingEventId: 6,
+				AcceptedRequest:                  &updatepb.Request{Meta: updateMeta, Input: &updatepb.Input{Name: updateName, Args: updateArgs}},
+			},
+		}},
+		{EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_UPDATE_COMPLETED, Attributes: &historypb.HistoryEvent_WorkflowExecutionUpdateCompletedEventAttributes{
+			WorkflowExecutionUpdateCompletedEventAttributes: &historypb.WorkflowExecutionUpdateCompletedEventAttributes{
+				Meta:    updateMeta,
+				Outcome: &updatepb.Outcome{Value: &updatepb.Outcome_Success{Success: updateResult}},
+			},
+		}},
+		{EventType: enumspb.EVENT_TYPE_TIMER_FIRED, Attributes: &historypb.HistoryEvent_TimerFiredEventAttributes{
+			TimerFiredEventAttributes: &historypb.TimerFiredEventAttributes{TimerId: "5", StartedEventId: 5},
+		}},
+		workflowTaskEvent(enumspb.EVENT_TYPE_WORKFLOW_TASK_SCHEDULED),
+		workflowTaskEvent(enumspb.EVENT_TYPE_WORKFLOW_TASK_STARTED),
+		workflowTaskEvent(enumspb.EVENT_TYPE_WORKFLOW_TASK_COMPLETED),
+		{EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_COMPLETED, Attributes: &historypb.HistoryEvent_WorkflowExecutionCompletedEventAttributes{
+			WorkflowExecutionCompletedEventAttributes: &historypb.WorkflowExecutionCompletedEventAttributes{Result: result, WorkflowTaskCompletedEventId: 14},
+		}},
+	}
+	for i, event := range events {
+		event.EventId = int64(i + 1)
+	}
+	return &historypb.History{Events: events}
+}
+
+// workflowTaskEvent returns a Workflow Task Event of eventType, with the attributes that replay needs.
+func workflowTaskEvent(eventType enumspb.EventType) *historypb.HistoryEvent {
+	event := &historypb.HistoryEvent{EventType: eventType}
+	switch eventType {
+	case enumspb.EVENT_TYPE_WORKFLOW_TASK_SCHEDULED:
+		event.Attributes = &historypb.HistoryEvent_WorkflowTaskScheduledEventAttributes{
+			WorkflowTaskScheduledEventAttributes: &historypb.WorkflowTaskScheduledEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: TaskQueueName}},
+		}
+	case enumspb.EVENT_TYPE_WORKFLOW_TASK_STARTED:
+		event.Attributes = &historypb.HistoryEvent_WorkflowTaskStartedEventAttributes{
+			WorkflowTaskStartedEventAttributes: &historypb.WorkflowTaskStartedEventAttributes{},
+		}
+	case enumspb.EVENT_TYPE_WORKFLOW_TASK_COMPLETED:
+		event.Attributes = &historypb.HistoryEvent_WorkflowTaskCompletedEventAttributes{
+			WorkflowTaskCompletedEventAttributes: &historypb.WorkflowTaskCompletedEventAttributes{},
+		}
+	}
+	return event
+}
+
+// Runs that started before LifecycleChangeID, which slept for 60 seconds, replay without nondeterminism errors,
+// even though an Update arrived during the sleep: an idle timeout would have restarted the timer.
+func Test_UpdatableWorkflows_ReplayRunsFromBeforeTheLifecycle(t *testing.T) {
+	tests := []struct {
+		workflowFn interface{}
+		name       string
+		updateName string
+	}{
+		{YourUpdatableWorkflow, "YourUpdatableWorkflow", YourUpdateName},
+		{UpdatableWorkflowWithValidator, "UpdatableWorkflowWithValidator", YourValidatedUpdateName},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			replayer := worker.NewWorkflowReplayer()
+			replayer.RegisterWorkflow(test.workflowFn)
+			err := replayer.ReplayWorkflowHistory(nil, legacyHistory(t, test.name, test.updateName))
+			require.NoError(t, err)
+		})
+	}
+}
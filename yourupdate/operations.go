@@ -0,0 +1,160 @@
+package yourupdate
+
+import (
+	"math"
+
+	"go.temporal.io/sdk/workflow"
+
+	"documentation-samples-go/yourupdate/validate"
+)
+
+/*
+Besides adding, the counter supports typed operations behind a single Update name.
+Each operation has its own validator, built from the validate package, so invalid operations are rejected before they are recorded in the Event History.
+The validators also reject any operation whose result would fall outside MinTotal and MaxTotal.
+Clients can tell rejections apart with validate.CodeOf.
+
+The operations were added after Workflows had already run.
+Runs that started without them replay through workflow.GetVersion and never register the handler.
+*/
+
+// YourOperationUpdateName is the name of the Update that applies a typed operation to the counter.
+const YourOperationUpdateName = "your_operation_update_name"
+
+// TypedOperationsChangeID is the workflow.GetVersion change Id of the typed operations.
+const TypedOperationsChangeID = "typed-operations"
+
+const (
+	// MinTotal is the smallest total that an operation may produce.
+	MinTotal = -1000000
+	// MaxTotal is the largest total that an operation may produce.
+	MaxTotal = 1000000
+)
+
+// Operation is the type of an operation on the counter.
+type Operation string
+
+const (
+	// OperationAdd adds a positive Operand.
+	OperationAdd Operation = "add"
+	// OperationSubtract subtracts a positive Operand.
+	OperationSubtract Operation = "subtract"
+	// OperationSet sets the total to Operand.
+	OperationSet Operation = "set"
+	// OperationReset sets the total to zero. It takes no Operand.
+	OperationReset Operation = "reset"
+	// OperationMultiply multiplies the total by Operand.
+	OperationMultiply Operation = "multiply"
+)
+
+// YourOperationArg defines the structure of the operation Update argument.
+type YourOperationArg struct {
+	Operation Operation
+	Operand   int
+	// UpdateID is recorded in the audit log.
+	UpdateID string
+}
+
+// YourOperationResult defines the structure of the operation Update result.
+type YourOperationResult struct {
+	Operation Operation
+	Before    int
+	After     int
+}
+
+// operation validates and applies one Operation.
+type operation struct {
+	validate validate.Func[int, YourOperationArg]
+	apply    func(total, operand int) int
+}
+
+func operand(arg YourOperationArg) int { return arg.Operand }
+
+func kind(arg YourOperationArg) Operation { return arg.Operation }
+
+var operations = map[Operation]operation{
+	OperationAdd: {
+		validate: validate.InRange[int]("add operand", 1, math.MaxInt, operand),
+		apply:    func(total, operand int) int { return total + operand },
+	},
+	OperationSubtract: {
+		validate: validate.InRange[int]("subtract operand", 1, math.MaxInt, operand),
+		apply:    func(total, operand int) int { return total - operand },
+	},
+	OperationSet: {
+		validate: validate.All[int, YourOperationArg](),
+		apply:    func(total, operand int) int { return operand },
+	},
+	OperationReset: {
+		// Reset takes no operand.
+		validate: validate.InRange[int]("reset operand", 0, 0, operand),
+		apply:    func(total, operand int) int { return 0 },
+	},
+	OperationMultiply: {
+		validate: validate.All[int, YourOperationArg](),
+		apply:    multiply,
+	},
+}
+
+// knownOperations lists the Operations in a fixed order.
+var knownOperations = []Operation{OperationAdd, OperationSubtract, OperationSet, OperationReset, OperationMultiply}
+
+// multiply returns total * operand, saturated to the int range instead of overflowing.
+func multiply(total, operand int) int {
+	product := total * operand
+	if total != 0 && (product/total != operand || (total == -1 && operand == math.MinInt)) {
+		if (total < 0) == (operand < 0) {
+			return math.MaxInt
+		}
+		return math.MinInt
+	}
+	return product
+}
+
+// validateOperation is a validator function.
+// It checks that the Operation is known, runs its validator,
+// and checks that the result stays within MinTotal and MaxTotal.
+// This function can not change the state of the Workflow.
+var validateOperation = validate.All(
+	validate.AllowList[int]("operation", knownOperations, kind),
+	func(ctx workflow.Context, total int, arg YourOperationArg) error {
+		return operations[arg.Operation].validate(ctx, total, arg)
+	},
+	validate.ResultInRange("total", MinTotal, MaxTotal, func(total int, arg YourOperationArg) int {
+		return operations[arg.Operation].apply(total, arg.Operand)
+	}),
+)
+
+// setOperationHandler registers the operation Update handler on runs that support it.
+func setOperationHandler(ctx workflow.Context, counter *int, lc *lifecycle, audit *auditLog) error {
+	version := workflow.GetVersion(ctx, TypedOperationsChangeID, workflow.DefaultVersion, 1)
+	if version == workflow.DefaultVersion {
+		return nil
+	}
+	return workflow.SetUpdateHandlerWithOptions(
+		ctx, YourOperationUpdateName,
+		func(ctx workflow.Context, arg YourOperationArg) (YourOperationResult, error) {
+			err := lc.begin()
+			if err != nil {
+				return YourOperationResult{}, err
+			}
+			defer lc.end()
+			before := *counter
+			*counter = operations[arg.Operation].apply(before, arg.Operand)
+			audit.add(ctx, AuditEntry{
+				UpdateID:  arg.UpdateID,
+				Operation: arg.Operation,
+				Addend:    *counter - before,
+				Total:     *counter,
+			})
+			return YourOperationResult{Operation: arg.Operation, Before: before, After: *counter}, nil
+		},
+		workflow.UpdateHandlerOptions{Validator: func(ctx workflow.Context, arg YourOperationArg) error {
+			err := lc.validate()
+			if err != nil {
+				return err
+			}
+			return validateOperation(ctx, *counter, arg)
+		}},
+	)
+}
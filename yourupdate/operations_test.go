@@ -0,0 +1,116 @@
+package yourupdate
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+
+	"documentation-samples-go/yourupdate/validate"
+)
+
+// runOperation starts YourUpdatableWorkflow with total, sends arg and closes the Workflow.
+func runOperation(t *testing.T, total int, arg YourOperationArg) *updateCallbacks {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	callbacks := &updateCallbacks{}
+	env.RegisterDelayedCallback(func() {
+		env.UpdateWorkflow(YourOperationUpdateName, callbacks, arg)
+		env.SignalWorkflow(YourCloseSignalName, nil)
+	}, time.Second)
+	env.ExecuteWorkflow(YourUpdatableWorkflow, WFParam{StartCount: total})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var result WFResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	if callbacks.accepted {
+		require.Equal(t, callbacks.result.(YourOperationResult).After, result.EndTotal)
+	} else {
+		require.Equal(t, total, result.EndTotal)
+	}
+	return callbacks
+}
+
+func Test_Operations(t *testing.T) {
+	tests := []struct {
+		name   string
+		total  int
+		arg    YourOperationArg
+		before int
+		after  int
+	}{
+		{"add", 5, YourOperationArg{Operation: OperationAdd, Operand: 3}, 5, 8},
+		{"subtract", 5, YourOperationArg{Operation: OperationSubtract, Operand: 8}, 5, -3},
+		{"set", 5, YourOperationArg{Operation: OperationSet, Operand: -42}, 5, -42},
+		{"reset", 5, YourOperationArg{Operation: OperationReset}, 5, 0},
+		{"multiply", 5, YourOperationArg{Operation: OperationMultiply, Operand: -4}, 5, -20},
+		{"multiply to bound", 1000, YourOperationArg{Operation: OperationMultiply, Operand: 1000}, 1000, MaxTotal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callbacks := runOperation(t, tt.total, tt.arg)
+			require.True(t, callbacks.accepted)
+			require.NoError(t, callbacks.err)
+			require.Equal(t, YourOperationResult{Operation: tt.arg.Operation, Before: tt.before, After: tt.after}, callbacks.result)
+		})
+	}
+}
+
+func Test_Operations_Rejected(t *testing.T) {
+	tests := []struct {
+		name  string
+		total int
+		arg   YourOperationArg
+		code  validate.Code
+	}{
+		{"unknown operation", 5, YourOperationArg{Operation: "divide", Operand: 2}, validate.CodeNotAllowed},
+		{"add non-positive", 5, YourOperationArg{Operation: OperationAdd, Operand: 0}, validate.CodeOutOfRange},
+		{"subtract non-positive", 5, YourOperationArg{Operation: OperationSubtract, Operand: -1}, validate.CodeOutOfRange},
+		{"reset with operand", 5, YourOperationArg{Operation: OperationReset, Operand: 1}, validate.CodeOutOfRange},
+		{"set out of bounds", 5, YourOperationArg{Operation: OperationSet, Operand: MaxTotal + 1}, validate.CodeResultOutOfRange},
+		{"add out of bounds", MaxTotal, YourOperationArg{Operation: OperationAdd, Operand: 1}, validate.CodeResultOutOfRange},
+		{"subtract out of bounds", MinTotal, YourOperationArg{Operation: OperationSubtract, Operand: 1}, validate.CodeResultOutOfRange},
+		{"multiply out of bounds", 1001, YourOperationArg{Operation: OperationMultiply, Operand: 1000}, validate.CodeResultOutOfRange},
+		{"multiply overflow", MaxTotal, YourOperationArg{Operation: OperationMultiply, Operand: 1 << 62}, validate.CodeResultOutOfRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callbacks := runOperation(t, tt.total, tt.arg)
+			require.False(t, callbacks.accepted)
+			code, ok := validate.CodeOf(callbacks.rejected)
+			require.True(t, ok, callbacks.rejected)
+			require.Equal(t, tt.code, code)
+		})
+	}
+}
+
+func Test_Operations_NotRegisteredBeforeVersion(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	// Replay a run that started before the typed operations were added.
+	env.OnGetVersion(TypedOperationsChangeID, workflow.DefaultVersion, 1).Return(workflow.DefaultVersion)
+	callbacks := &updateCallbacks{}
+	env.RegisterDelayedCallback(func() {
+		env.UpdateWorkflow(YourOperationUpdateName, callbacks, YourOperationArg{Operation: OperationAdd, Operand: 1})
+		env.UpdateWorkflow(YourUpdateName, &updateCallbacks{}, YourUpdateArg{Add: 1})
+		env.SignalWorkflow(YourCloseSignalName, nil)
+	}, time.Second)
+
+	env.ExecuteWorkflow(YourUpdatableWorkflow, WFParam{StartCount: 1})
+	require.NoError(t, env.GetWorkflowError())
+	require.Error(t, callbacks.rejected)
+	var result WFResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, 2, result.EndTotal)
+}
+
+func Test_multiply(t *testing.T) {
+	require.Equal(t, -20, multiply(5, -4))
+	require.Equal(t, math.MaxInt, multiply(math.MaxInt/2+1, 2))
+	require.Equal(t, math.MinInt, multiply(math.MaxInt/2+1, -2))
+	require.Equal(t, math.MaxInt, multiply(-1, math.MinInt))
+	require.Equal(t, 0, multiply(0, math.MinInt))
+}
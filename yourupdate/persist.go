@@ -0,0 +1,197 @@
+package yourupdate
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"documentation-samples-go/yourupdate/store"
+)
+
+/*
+PersistentUpdatableWorkflow is a variant of YourUpdatableWorkflow for counters that must be kept in a datastore.
+Its Update handler persists the new total through an Activity before it returns,
+so a Client only sees a total once it is stored.
+
+Updates that arrive together are persisted one at a time, in the order they were accepted,
+so that each stored total includes all the Updates before it.
+If the Activity fails, the handler restores the previous total, stores it again with a higher Sequence,
+in case the failed write reached the store after all, and returns the error.
+While a write is running, the total Query already includes it.
+
+On start, the Workflow loads the stored total of its Workflow Id, if there is one,
+so a new Workflow with the same Id continues from the stored total.
+A run that continues as new starts from the total handed over by the previous run instead,
+which only loses to the store if a rollback could not be stored.
+*/
+
+// YourPersistentUpdateName is the name of the Update of PersistentUpdatableWorkflow.
+const YourPersistentUpdateName = "your_persistent_update_name"
+
+// YourPersistentUpdateWFID is the Id used for the PersistentUpdatableWorkflow execution.
+const YourPersistentUpdateWFID = "persistent_updatable_workflow"
+
+// PersistTotalParam defines the structure of the PersistTotal Activity argument.
+type PersistTotalParam struct {
+	// Key is the Workflow Id of the counter.
+	Key string
+	store.Record
+}
+
+// LoadTotalResult defines the structure of the LoadTotal Activity result.
+type LoadTotalResult struct {
+	Found bool
+	store.Record
+}
+
+// PersistActivities holds the Activities that read and write the store.
+type PersistActivities struct {
+	Store store.Store
+}
+
+// LoadTotal returns the stored total of key, if there is one.
+func (a *PersistActivities) LoadTotal(ctx context.Context, key string) (LoadTotalResult, error) {
+	rec, err := a.Store.Load(ctx, key)
+	if errors.Is(err, store.ErrNotFound) {
+		return LoadTotalResult{}, nil
+	}
+	if err != nil {
+		return LoadTotalResult{}, err
+	}
+	return LoadTotalResult{Found: true, Record: rec}, nil
+}
+
+// PersistTotal stores a new total.
+func (a *PersistActivities) PersistTotal(ctx context.Context, param PersistTotalParam) error {
+	return a.Store.Save(ctx, param.Key, param.Record)
+}
+
+// persistActivityOptions bounds the retries of the store Activities,
+// so that an Update fails, and its total is rolled back, when the store stays unavailable.
+var persistActivityOptions = workflow.ActivityOptions{
+	StartToCloseTimeout: 10 * time.Second,
+	RetryPolicy: &temporal.RetryPolicy{
+		InitialInterval: time.Second,
+		MaximumAttempts: 3,
+	},
+}
+
+// persistedCounter is the total of PersistentUpdatableWorkflow.
+type persistedCounter struct {
+	key   string
+	total int
+	// sequence numbers the writes. It is never rolled back, so that the write of
+	// a rolled back total replaces a failed write that reached the store.
+	sequence int
+	busy     bool
+}
+
+// add adds addend to the total and persists it, and returns the new total.
+// Calls wait for each other, so that only one write is running at a time.
+func (c *persistedCounter) add(ctx workflow.Context, addend int) (int, error) {
+	err := workflow.Await(ctx, func() bool {
+		return !c.busy
+	})
+	if err != nil {
+		return 0, err
+	}
+	c.busy = true
+	defer func() {
+		c.busy = false
+	}()
+	before := c.total
+	c.total += addend
+	c.sequence++
+	// The Update handler gets its own Context, so set the Activity options here.
+	ctx = workflow.WithActivityOptions(ctx, persistActivityOptions)
+	var a *PersistActivities
+	err = workflow.ExecuteActivity(ctx, a.PersistTotal, PersistTotalParam{
+		Key:    c.key,
+		Record: store.Record{Total: c.total, Sequence: c.sequence},
+	}).Get(ctx, nil)
+	if err != nil {
+		logger := workflow.GetLogger(ctx)
+		logger.Warn("Unable to persist total, rolling back", "Total", c.total, "RolledBackTo", before, "Error", err)
+		c.total = before
+		c.sequence++
+		rollbackErr := workflow.ExecuteActivity(ctx, a.PersistTotal, PersistTotalParam{
+			Key:    c.key,
+			Record: store.Record{Total: c.total, Sequence: c.sequence},
+		}).Get(ctx, nil)
+		if rollbackErr != nil {
+			// The next write, or the next run, stores the right total again.
+			logger.Error("Unable to persist rolled back total", "Total", c.total, "Error", rollbackErr)
+		}
+		return 0, err
+	}
+	return c.total, nil
+}
+
+// PersistentUpdatableWorkflow is a Workflow Definition.
+// Its Update handler persists every new total through the PersistTotal Activity.
+// Like YourUpdatableWorkflow, it finishes when it receives the close Signal or when no Update arrives for the idle timeout,
+// and continues as new after many Updates.
+func PersistentUpdatableWorkflow(ctx workflow.Context, param WFParam) (WFResult, error) {
+	ctx = workflow.WithActivityOptions(ctx, persistActivityOptions)
+	counter := &persistedCounter{
+		key:   workflow.GetInfo(ctx).WorkflowExecution.ID,
+		total: param.StartCount,
+	}
+	var a *PersistActivities
+	var loaded LoadTotalResult
+	err := workflow.ExecuteActivity(ctx, a.LoadTotal, counter.key).Get(ctx, &loaded)
+	if err != nil {
+		return WFResult{}, err
+	}
+	if loaded.Found {
+		// The previous run hands over its total as StartCount, which is right even when
+		// the store still holds a failed write whose rollback could not be stored.
+		if workflow.GetInfo(ctx).ContinuedExecutionRunID == "" {
+			counter.total = loaded.Total
+		}
+		counter.sequence = loaded.Sequence
+	}
+	lc := newLifecycle(ctx, param)
+	audit := newAuditLog(MaxAuditLogEntries)
+	err = setQueryHandlers(ctx, &counter.total, audit)
+	if err != nil {
+		return WFResult{}, err
+	}
+	err = workflow.SetUpdateHandlerWithOptions(
+		ctx, YourPersistentUpdateName,
+		func(ctx workflow.Context, arg YourUpdateArg) (YourUpdateResult, error) {
+			err := lc.begin()
+			if err != nil {
+				return YourUpdateResult{}, err
+			}
+			defer lc.end()
+			total, err := counter.add(ctx, arg.Add)
+			if err != nil {
+				return YourUpdateResult{}, err
+			}
+			audit.add(ctx, AuditEntry{UpdateID: arg.UpdateID, Operation: OperationAdd, Addend: arg.Add, Total: total})
+			return YourUpdateResult{Total: total}, nil
+		},
+		// Reject Updates before they are recorded once the Workflow is closing.
+		workflow.UpdateHandlerOptions{Validator: func(ctx workflow.Context, arg YourUpdateArg) error {
+			return lc.validate()
+		}},
+	)
+	if err != nil {
+		return WFResult{}, err
+	}
+	// Wait for Updates until the Workflow is closed or idle.
+	err = lc.wait(ctx, param.IdleTimeout)
+	if err != nil {
+		return WFResult{}, err
+	}
+	// Hand over to a new run once this run has handled enough Updates.
+	err = lc.continueAsNew(ctx, PersistentUpdatableWorkflow, param, counter.total)
+	if err != nil {
+		return WFResult{}, err
+	}
+	return WFResult{EndTotal: counter.total}, nil
+}
@@ -0,0 +1,202 @@
+package yourupdate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"documentation-samples-go/yourupdate/store"
+	"documentation-samples-go/yourupdate/validate"
+)
+
+// testWorkflowID is the Workflow Id used by the test environment.
+const testWorkflowID = "default-test-workflow-id"
+
+// failingStore fails every write of failTotal.
+type failingStore struct {
+	store.Store
+	failTotal int
+}
+
+func (s *failingStore) Save(ctx context.Context, key string, rec store.Record) error {
+	if rec.Total == s.failTotal {
+		return errors.New("datastore unavailable")
+	}
+	return s.Store.Save(ctx, key, rec)
+}
+
+// lostAckStore stores every write of lostTotal, then fails it, as if the
+// Activity timed out after the datastore had committed the write.
+type lostAckStore struct {
+	store.Store
+	lostTotal int
+}
+
+func (s *lostAckStore) Save(ctx context.Context, key string, rec store.Record) error {
+	err := s.Store.Save(ctx, key, rec)
+	if err == nil && rec.Total == s.lostTotal {
+		return errors.New("datastore timed out")
+	}
+	return err
+}
+
+// blockingStore holds every write until release is closed.
+type blockingStore struct {
+	store.Store
+	release chan struct{}
+}
+
+func (s *blockingStore) Save(ctx context.Context, key string, rec store.Record) error {
+	<-s.release
+	return s.Store.Save(ctx, key, rec)
+}
+
+func Test_PersistentUpdatableWorkflow_SerializesConcurrentUpdates(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	memoryStore := store.NewMemoryStore()
+	env.RegisterActivity(&PersistActivities{Store: memoryStore})
+	updates := make([]*updateCallbacks, 3)
+	env.RegisterDelayedCallback(func() {
+		for i := range updates {
+			updates[i] = &updateCallbacks{}
+			env.UpdateWorkflow(YourPersistentUpdateName, updates[i], YourUpdateArg{Add: i + 1})
+		}
+	}, time.Second)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(YourCloseSignalName, nil)
+	}, time.Minute)
+
+	env.ExecuteWorkflow(PersistentUpdatableWorkflow, WFParam{IdleTimeout: time.Hour})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	// Each Update sees the ones accepted before it.
+	for i, total := range []int{1, 3, 6} {
+		require.NoError(t, updates[i].err)
+		require.Equal(t, YourUpdateResult{Total: total}, updates[i].result)
+	}
+	rec, err := memoryStore.Load(context.Background(), testWorkflowID)
+	require.NoError(t, err)
+	require.Equal(t, store.Record{Total: 6, Sequence: 3}, rec)
+	var result WFResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, 6, result.EndTotal)
+}
+
+func Test_PersistentUpdatableWorkflow_RollsBackFailedWrites(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	memoryStore := store.NewMemoryStore()
+	env.RegisterActivity(&PersistActivities{Store: &failingStore{Store: memoryStore, failTotal: 11}})
+	updates := make([]*updateCallbacks, 3)
+	env.RegisterDelayedCallback(func() {
+		for i, add := range []int{1, 10, 2} {
+			updates[i] = &updateCallbacks{}
+			env.UpdateWorkflow(YourPersistentUpdateName, updates[i], YourUpdateArg{Add: add})
+		}
+	}, time.Second)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(YourCloseSignalName, nil)
+	}, time.Minute)
+
+	env.ExecuteWorkflow(PersistentUpdatableWorkflow, WFParam{IdleTimeout: time.Hour})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Equal(t, YourUpdateResult{Total: 1}, updates[0].result)
+	require.ErrorContains(t, updates[1].err, "datastore unavailable")
+	// The failed Update was rolled back before the next one ran.
+	require.Equal(t, YourUpdateResult{Total: 3}, updates[2].result)
+	rec, err := memoryStore.Load(context.Background(), testWorkflowID)
+	require.NoError(t, err)
+	require.Equal(t, store.Record{Total: 3, Sequence: 4}, rec)
+	var result WFResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, 3, result.EndTotal)
+}
+
+func Test_PersistentUpdatableWorkflow_OverwritesFailedWritesThatReachedTheStore(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	memoryStore := store.NewMemoryStore()
+	env.RegisterActivity(&PersistActivities{Store: &lostAckStore{Store: memoryStore, lostTotal: 11}})
+	updates := make([]*updateCallbacks, 2)
+	env.RegisterDelayedCallback(func() {
+		for i, add := range []int{1, 10} {
+			updates[i] = &updateCallbacks{}
+			env.UpdateWorkflow(YourPersistentUpdateName, updates[i], YourUpdateArg{Add: add})
+		}
+	}, time.Second)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(YourCloseSignalName, nil)
+	}, time.Minute)
+
+	env.ExecuteWorkflow(PersistentUpdatableWorkflow, WFParam{IdleTimeout: time.Hour})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Equal(t, YourUpdateResult{Total: 1}, updates[0].result)
+	require.ErrorContains(t, updates[1].err, "datastore timed out")
+	// The store holds the rolled back total, not the failed write, so a new run starts from it.
+	rec, err := memoryStore.Load(context.Background(), testWorkflowID)
+	require.NoError(t, err)
+	require.Equal(t, store.Record{Total: 1, Sequence: 3}, rec)
+	var result WFResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, 1, result.EndTotal)
+}
+
+func Test_PersistentUpdatableWorkflow_ContinuesFromStoredTotal(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	memoryStore := store.NewMemoryStore()
+	require.NoError(t, memoryStore.Save(context.Background(), testWorkflowID, store.Record{Total: 40, Sequence: 7}))
+	env.RegisterActivity(&PersistActivities{Store: memoryStore})
+	update := &updateCallbacks{}
+	env.RegisterDelayedCallback(func() {
+		env.UpdateWorkflow(YourPersistentUpdateName, update, YourUpdateArg{Add: 2})
+	}, time.Second)
+
+	env.ExecuteWorkflow(PersistentUpdatableWorkflow, WFParam{StartCount: 1})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Equal(t, YourUpdateResult{Total: 42}, update.result)
+	rec, err := memoryStore.Load(context.Background(), testWorkflowID)
+	require.NoError(t, err)
+	require.Equal(t, store.Record{Total: 42, Sequence: 8}, rec)
+}
+
+func Test_PersistentUpdatableWorkflow_RejectsUpdatesAfterClose(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	blocking := &blockingStore{Store: store.NewMemoryStore(), release: make(chan struct{})}
+	env.RegisterActivity(&PersistActivities{Store: blocking})
+	first, late := &updateCallbacks{}, &updateCallbacks{}
+	env.RegisterDelayedCallback(func() {
+		env.UpdateWorkflow(YourPersistentUpdateName, first, YourUpdateArg{Add: 2})
+	}, time.Second)
+	// The first handler is still writing its total when the close Signal and the late Update arrive.
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(YourCloseSignalName, nil)
+	}, time.Second+100*time.Millisecond)
+	env.RegisterDelayedCallback(func() {
+		env.UpdateWorkflow(YourPersistentUpdateName, late, YourUpdateArg{Add: 3})
+		close(blocking.release)
+	}, time.Second+200*time.Millisecond)
+
+	env.ExecuteWorkflow(PersistentUpdatableWorkflow, WFParam{IdleTimeout: time.Hour})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.NoError(t, first.err)
+	require.Equal(t, YourUpdateResult{Total: 2}, first.result)
+	require.False(t, late.accepted)
+	require.ErrorIs(t, late.rejected, ErrClosing)
+	code, ok := validate.CodeOf(late.rejected)
+	require.True(t, ok)
+	require.Equal(t, validate.CodeClosing, code)
+	var result WFResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, 2, result.EndTotal)
+}
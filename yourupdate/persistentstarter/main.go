@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"go.temporal.io/sdk/client"
+
+	"documentation-samples-go/codec"
+	"documentation-samples-go/yourupdate"
+)
+
+func main() {
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		log.Fatalln("Unable to create Data Converter", err)
+	}
+	temporalClient, err := client.Dial(client.Options{
+		HostPort:      client.DefaultHostPort,
+		DataConverter: dataConverter,
+	})
+	if err != nil {
+		log.Fatalln("Unable to create client", err)
+	}
+	defer temporalClient.Close()
+	workflowOptions := client.StartWorkflowOptions{
+		ID:        yourupdate.YourPersistentUpdateWFID,
+		TaskQueue: yourupdate.TaskQueueName,
+	}
+	startingCount := yourupdate.WFParam{
+		StartCount: 0,
+	}
+	we, err := temporalClient.ExecuteWorkflow(context.Background(), workflowOptions, yourupdate.PersistentUpdatableWorkflow, startingCount)
+	if err != nil {
+		log.Fatalln("Unable to execute workflow", err)
+	}
+	log.Println("Started workflow", "WorkflowID", we.GetID(), "RunID", we.GetRunID())
+}
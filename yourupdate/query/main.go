@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"go.temporal.io/sdk/client"
+
+	"documentation-samples-go/codec"
+	"documentation-samples-go/yourupdate"
+)
+
+func main() {
+	// Query the YourUpdatableWorkflow execution unless another Workflow Id is given.
+	workflowID := yourupdate.YourUpdateWFID
+	if len(os.Args) == 2 {
+		workflowID = os.Args[1]
+	}
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		log.Fatalln("Unable to create Data Converter", err)
+	}
+	temporalClient, err := client.Dial(client.Options{
+		HostPort:      client.DefaultHostPort,
+		DataConverter: dataConverter,
+	})
+	if err != nil {
+		log.Fatalln("Unable to create client", err)
+	}
+	defer temporalClient.Close()
+	value, err := temporalClient.QueryWorkflow(context.Background(), workflowID, "", yourupdate.YourTotalQueryName)
+	if err != nil {
+		log.Fatalln("Unable to query total", err)
+	}
+	var total int
+	err = value.Get(&total)
+	if err != nil {
+		log.Fatalln("Unable to decode total", err)
+	}
+	log.Println("Current total: ", total)
+	// Read the audit log one page at a time.
+	request := yourupdate.AuditLogPageRequest{}
+	for {
+		value, err = temporalClient.QueryWorkflow(context.Background(), workflowID, "", yourupdate.YourAuditLogQueryName, request)
+		if err != nil {
+			log.Fatalln("Unable to query audit log", err)
+		}
+		var page yourupdate.AuditLogPage
+		err = value.Get(&page)
+		if err != nil {
+			log.Fatalln("Unable to decode audit log", err)
+		}
+		if request.PageToken == 0 && page.Dropped > 0 {
+			log.Println("Older entries dropped: ", page.Dropped)
+		}
+		for _, entry := range page.Entries {
+			log.Println(entry.Sequence, entry.Time.Format("15:04:05"), "UpdateID", entry.UpdateID, "Add", entry.Addend, "Total", entry.Total)
+		}
+		if page.NextPageToken == 0 {
+			break
+		}
+		request.PageToken = page.NextPageToken
+	}
+}
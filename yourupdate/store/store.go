@@ -0,0 +1,163 @@
+// Package store persists counter totals outside of the Workflow.
+//
+// Every write carries a Sequence that grows with each change of the total.
+// A Store keeps the record with the highest Sequence, so a retried or delayed
+// write never overwrites a newer total.
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrNotFound is returned by Store.Load when no total is stored for the key.
+var ErrNotFound = errors.New("total not found")
+
+// Record is a persisted total.
+type Record struct {
+	Total    int
+	Sequence int
+}
+
+// Store persists the total of each counter, keyed by Workflow Id.
+// Implement it to use a real datastore.
+type Store interface {
+	// Save stores rec under key, unless the stored record has a higher Sequence.
+	// Saving the same record again is not an error.
+	Save(ctx context.Context, key string, rec Record) error
+	// Load returns the record stored under key, or ErrNotFound.
+	Load(ctx context.Context, key string) (Record, error)
+}
+
+// FileStore is a Store that keeps each record in a JSON file in a directory.
+// It serializes writes within one process, so run a single Worker process per directory.
+type FileStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+var _ Store = (*FileStore)(nil)
+
+// NewFileStore returns a FileStore in dir, creating dir if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// path returns the file of key. Keys are escaped so that they can't leave the directory.
+func (s *FileStore) path(key string) (string, error) {
+	if key == "" || key == "." || key == ".." {
+		return "", fmt.Errorf("invalid key %q", key)
+	}
+	return filepath.Join(s.dir, url.PathEscape(key)+".json"), nil
+}
+
+// Save implements Store.
+func (s *FileStore) Save(ctx context.Context, key string, rec Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, err := s.load(key)
+	if err == nil && current.Sequence > rec.Sequence {
+		return nil
+	}
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first, so that readers never see a partial record.
+	f, err := os.CreateTemp(s.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+// Load implements Store.
+func (s *FileStore) Load(ctx context.Context, key string) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load(key)
+}
+
+func (s *FileStore) load(key string) (Record, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return Record{}, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Record{}, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	if err != nil {
+		return Record{}, err
+	}
+	var rec Record
+	err = json.Unmarshal(data, &rec)
+	if err != nil {
+		return Record{}, fmt.Errorf("invalid record for %s: %w", key, err)
+	}
+	return rec, nil
+}
+
+// MemoryStore is a Store that keeps records in memory.
+// Use it in tests, or as a stand-in for a real datastore.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: map[string]Record{}}
+}
+
+// Save implements Store.
+func (s *MemoryStore) Save(ctx context.Context, key string, rec Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if current, ok := s.records[key]; ok && current.Sequence > rec.Sequence {
+		return nil
+	}
+	s.records[key] = rec
+	return nil
+}
+
+// Load implements Store.
+func (s *MemoryStore) Load(ctx context.Context, key string) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec, ok := s.records[key]
+	if !ok {
+		return Record{}, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return rec, nil
+}
@@ -0,0 +1,61 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newStores(t *testing.T) map[string]Store {
+	fileStore, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	return map[string]Store{
+		"file":   fileStore,
+		"memory": NewMemoryStore(),
+	}
+}
+
+func Test_Store_SaveLoad(t *testing.T) {
+	ctx := context.Background()
+	for name, s := range newStores(t) {
+		t.Run(name, func(t *testing.T) {
+			_, err := s.Load(ctx, "counter/1")
+			require.ErrorIs(t, err, ErrNotFound)
+
+			require.NoError(t, s.Save(ctx, "counter/1", Record{Total: 5, Sequence: 1}))
+			require.NoError(t, s.Save(ctx, "counter/1", Record{Total: 8, Sequence: 2}))
+			rec, err := s.Load(ctx, "counter/1")
+			require.NoError(t, err)
+			require.Equal(t, Record{Total: 8, Sequence: 2}, rec)
+
+			// Other keys are independent.
+			_, err = s.Load(ctx, "counter")
+			require.ErrorIs(t, err, ErrNotFound)
+		})
+	}
+}
+
+func Test_Store_IgnoresStaleWrites(t *testing.T) {
+	ctx := context.Background()
+	for name, s := range newStores(t) {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, s.Save(ctx, "counter", Record{Total: 8, Sequence: 2}))
+			// A delayed retry of an older write.
+			require.NoError(t, s.Save(ctx, "counter", Record{Total: 5, Sequence: 1}))
+			// The same write again.
+			require.NoError(t, s.Save(ctx, "counter", Record{Total: 8, Sequence: 2}))
+			rec, err := s.Load(ctx, "counter")
+			require.NoError(t, err)
+			require.Equal(t, Record{Total: 8, Sequence: 2}, rec)
+		})
+	}
+}
+
+func Test_FileStore_RejectsInvalidKeys(t *testing.T) {
+	s, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	for _, key := range []string{"", ".", ".."} {
+		require.Error(t, s.Save(context.Background(), key, Record{Total: 1, Sequence: 1}))
+	}
+}
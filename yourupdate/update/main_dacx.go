@@ -9,6 +9,7 @@ import (
 
 	"go.temporal.io/sdk/client"
 
+	"documentation-samples-go/codec"
 	"documentation-samples-go/yourupdate"
 )
 
@@ -32,9 +33,15 @@ func main() {
 		fmt.Println("The argument must be an integer")
 		os.Exit(1)
 	}
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		log.Fatalln("Unable to create Data Converter", err)
+	}
 	// Create a Temporal Client.
 	temporalClient, err := client.Dial(client.Options{
-		HostPort: client.DefaultHostPort,
+		HostPort:      client.DefaultHostPort,
+		DataConverter: dataConverter,
 	})
 	if err != nil {
 		log.Fatalln("Unable to create client", err)
@@ -70,5 +77,5 @@ tags:
 - code sample
 - update
 - client
-lines: 15-22, 44-61
+lines: 16-23, 51-68
 @dacx */
@@ -0,0 +1,46 @@
+package validate
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+)
+
+// RateLimiter limits the number of Updates accepted in a sliding time window.
+// Because validators can't change Workflow state, the Update handler calls Record
+// for each Update it applies, and the validator from RateLimit rejects Updates over the limit.
+type RateLimiter struct {
+	limit    int
+	window   time.Duration
+	accepted []time.Time
+}
+
+// NewRateLimiter returns a RateLimiter that allows limit Updates per window.
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{limit: limit, window: window}
+}
+
+// Record records an applied Update. Call it from the Update handler.
+func (r *RateLimiter) Record(ctx workflow.Context) {
+	now := workflow.Now(ctx)
+	r.accepted = append(r.recent(now), now)
+}
+
+// recent returns the recorded times within the window that ends at now.
+func (r *RateLimiter) recent(now time.Time) []time.Time {
+	i := 0
+	for i < len(r.accepted) && !r.accepted[i].After(now.Add(-r.window)) {
+		i++
+	}
+	return r.accepted[i:]
+}
+
+// RateLimit returns a Func that rejects with CodeRateLimited when r's limit of Updates was already applied in the window.
+func RateLimit[S, A any](r *RateLimiter) Func[S, A] {
+	return func(ctx workflow.Context, state S, arg A) error {
+		if len(r.recent(workflow.Now(ctx))) >= r.limit {
+			return Reject(CodeRateLimited, "at most %d updates are accepted per %v", r.limit, r.window)
+		}
+		return nil
+	}
+}
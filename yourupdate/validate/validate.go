@@ -0,0 +1,168 @@
+// Package validate builds Update validators from small, composable checks.
+//
+// A Func checks an Update argument against the current state of the Workflow.
+// Combine Funcs with All, and adapt the result to workflow.UpdateHandlerOptions with For:
+//
+//	workflow.UpdateHandlerOptions{Validator: validate.For(func() int { return counter }, validate.All(
+//		validate.InRange[int]("Add", 1, 100, func(arg YourUpdateArg) int { return arg.Add }),
+//		validate.MaxResult(1000, func(total int, arg YourUpdateArg) int { return total + arg.Add }),
+//	))}
+//
+// Every rejection is a *temporal.ApplicationError whose type is a stable Code,
+// so a Client can branch on it with CodeOf.
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// Code identifies why an Update was rejected.
+// Codes are part of the API of the Workflow: don't change them.
+type Code string
+
+const (
+	// CodeOutOfRange means that a value of the argument is outside its allowed range.
+	CodeOutOfRange Code = "OUT_OF_RANGE"
+	// CodeResultOutOfRange means that applying the Update would put the state outside its allowed range.
+	CodeResultOutOfRange Code = "RESULT_OUT_OF_RANGE"
+	// CodeNotAllowed means that a value of the argument is not in its allow-list.
+	CodeNotAllowed Code = "NOT_ALLOWED"
+	// CodeRateLimited means that too many Updates were accepted in the current time window.
+	CodeRateLimited Code = "RATE_LIMITED"
+	// CodeRetryLater means that the Workflow can't take Updates right now, for example
+	// while it continues as new. The Update was not applied and can be sent again.
+	CodeRetryLater Code = "RETRY_LATER"
+	// CodeClosing means that the Workflow is closing and takes no more Updates.
+	// The Update was not applied, and sending it again is rejected the same way.
+	CodeClosing Code = "CLOSING"
+)
+
+// notAppliedType is the type of the errors that NotApplied returns. It is not a Code.
+const notAppliedType = "NOT_APPLIED"
+
+var codes = map[Code]bool{
+	CodeOutOfRange:       true,
+	CodeResultOutOfRange: true,
+	CodeNotAllowed:       true,
+	CodeRateLimited:      true,
+	CodeRetryLater:       true,
+	CodeClosing:          true,
+}
+
+// Reject returns a rejection error with code and a formatted message.
+func Reject(code Code, format string, args ...interface{}) error {
+	return temporal.NewApplicationError(fmt.Sprintf(format, args...), string(code))
+}
+
+// NotApplied wraps the error of an Update handler that returns before it changes the Workflow state,
+// such as a handler that finds the Workflow closing. The Update was accepted, so the error is not a rejection:
+// CodeOf reports no Code for it, even when err has one, while errors.Is and RetryLater still see err.
+func NotApplied(err error) error {
+	return temporal.NewApplicationErrorWithCause("update was accepted but not applied: "+err.Error(), notAppliedType, err)
+}
+
+// RetryLater reports whether err, or the error that NotApplied wrapped, has CodeRetryLater,
+// so that the Update was not applied and can be sent again.
+func RetryLater(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if applicationErr, ok := err.(*temporal.ApplicationError); ok && applicationErr.Type() == string(CodeRetryLater) {
+			return true
+		}
+	}
+	return false
+}
+
+// CodeOf returns the Code of a rejection returned by a validator.
+// It works both in the Workflow and on the error that a Client receives from an Update,
+// and reports no Code for the errors of NotApplied, whose Update was accepted.
+func CodeOf(err error) (Code, bool) {
+	var applicationErr *temporal.ApplicationError
+	if !errors.As(err, &applicationErr) {
+		return "", false
+	}
+	code := Code(applicationErr.Type())
+	return code, codes[code]
+}
+
+// Func validates an Update argument of type A against the Workflow state of type S.
+// Like every validator, it must not change the state.
+type Func[S, A any] func(ctx workflow.Context, state S, arg A) error
+
+// For adapts v to the Validator of workflow.UpdateHandlerOptions.
+// state is called for each Update to read the current Workflow state.
+func For[S, A any](state func() S, v Func[S, A]) func(ctx workflow.Context, arg A) error {
+	return func(ctx workflow.Context, arg A) error {
+		return v(ctx, state(), arg)
+	}
+}
+
+// All returns a Func that runs validators in order and returns the first rejection.
+func All[S, A any](validators ...Func[S, A]) Func[S, A] {
+	return func(ctx workflow.Context, state S, arg A) error {
+		for _, v := range validators {
+			err := v(ctx, state, arg)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// InRange rejects with CodeOutOfRange when the value that field reads from the argument is outside [min, max].
+func InRange[S, A any](name string, min, max int, field func(A) int) Func[S, A] {
+	return func(ctx workflow.Context, state S, arg A) error {
+		value := field(arg)
+		if value < min || value > max {
+			return Reject(CodeOutOfRange, "%s must be in %s (%v)", name, formatRange(min, max), value)
+		}
+		return nil
+	}
+}
+
+// ResultInRange rejects with CodeResultOutOfRange when the value that result computes
+// from the state and the argument is outside [min, max].
+func ResultInRange[S, A any](name string, min, max int, result func(S, A) int) Func[S, A] {
+	return func(ctx workflow.Context, state S, arg A) error {
+		value := result(state, arg)
+		if value < min || value > max {
+			return Reject(CodeResultOutOfRange, "resulting %s must be in %s (%v)", name, formatRange(min, max), value)
+		}
+		return nil
+	}
+}
+
+// MaxResult rejects with CodeResultOutOfRange when the total that result computes is above max.
+func MaxResult[S, A any](max int, result func(S, A) int) Func[S, A] {
+	return ResultInRange("total", math.MinInt, max, result)
+}
+
+// AllowList rejects with CodeNotAllowed when the value that field reads from the argument is not one of allowed.
+func AllowList[S, A any, V comparable](name string, allowed []V, field func(A) V) Func[S, A] {
+	set := make(map[V]bool, len(allowed))
+	for _, v := range allowed {
+		set[v] = true
+	}
+	return func(ctx workflow.Context, state S, arg A) error {
+		value := field(arg)
+		if !set[value] {
+			return Reject(CodeNotAllowed, "%s %v is not allowed", name, value)
+		}
+		return nil
+	}
+}
+
+func formatRange(min, max int) string {
+	switch {
+	case min == math.MinInt:
+		return fmt.Sprintf("(-inf, %d]", max)
+	case max == math.MaxInt:
+		return fmt.Sprintf("[%d, +inf)", min)
+	}
+	return fmt.Sprintf("[%d, %d]", min, max)
+}
@@ -0,0 +1,127 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+type arg struct {
+	Add  int
+	Kind string
+}
+
+func add(arg arg) int                   { return arg.Add }
+func kind(arg arg) string               { return arg.Kind }
+func addToTotal(total int, arg arg) int { return total + arg.Add }
+
+func Test_Validators(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator Func[int, arg]
+		total     int
+		arg       arg
+		code      Code
+	}{
+		{"in range", InRange[int]("Add", 1, 10, add), 0, arg{Add: 10}, ""},
+		{"below range", InRange[int]("Add", 1, 10, add), 0, arg{Add: 0}, CodeOutOfRange},
+		{"above range", InRange[int]("Add", 1, 10, add), 0, arg{Add: 11}, CodeOutOfRange},
+		{"result in range", ResultInRange("total", -5, 5, addToTotal), 3, arg{Add: -8}, ""},
+		{"result out of range", ResultInRange("total", -5, 5, addToTotal), 3, arg{Add: 3}, CodeResultOutOfRange},
+		{"max result", MaxResult(100, addToTotal), 90, arg{Add: 10}, ""},
+		{"max result exceeded", MaxResult(100, addToTotal), 90, arg{Add: 11}, CodeResultOutOfRange},
+		{"allowed", AllowList[int]("Kind", []string{"a", "b"}, kind), 0, arg{Kind: "b"}, ""},
+		{"not allowed", AllowList[int]("Kind", []string{"a", "b"}, kind), 0, arg{Kind: "c"}, CodeNotAllowed},
+		{"all pass", All(InRange[int]("Add", 1, 10, add), MaxResult(100, addToTotal)), 0, arg{Add: 5}, ""},
+		{"all first rejection", All(AllowList[int]("Kind", []string{"a"}, kind), InRange[int]("Add", 1, 10, add)), 0, arg{Kind: "c"}, CodeNotAllowed},
+		{"all second rejection", All(InRange[int]("Add", 1, 10, add), MaxResult(10, addToTotal)), 8, arg{Add: 5}, CodeResultOutOfRange},
+		{"all empty", All[int, arg](), 0, arg{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validator(nil, tt.total, tt.arg)
+			if tt.code == "" {
+				require.NoError(t, err)
+				return
+			}
+			code, ok := CodeOf(err)
+			require.True(t, ok, err)
+			require.Equal(t, tt.code, code)
+		})
+	}
+}
+
+func Test_For(t *testing.T) {
+	total := 0
+	validator := For(func() int { return total }, MaxResult(10, addToTotal))
+	require.NoError(t, validator(nil, arg{Add: 10}))
+	total = 1
+	require.Error(t, validator(nil, arg{Add: 10}))
+}
+
+func Test_CodeOf(t *testing.T) {
+	_, ok := CodeOf(errors.New("plain error"))
+	require.False(t, ok)
+	_, ok = CodeOf(nil)
+	require.False(t, ok)
+	code, ok := CodeOf(Reject(CodeRateLimited, "slow down"))
+	require.True(t, ok)
+	require.Equal(t, CodeRateLimited, code)
+}
+
+func Test_NotApplied(t *testing.T) {
+	rejection := Reject(CodeRetryLater, "busy")
+	// A Client receives the error converted to a Failure and back.
+	failureConverter := temporal.GetDefaultFailureConverter()
+	for _, err := range []error{NotApplied(rejection), failureConverter.FailureToError(failureConverter.ErrorToFailure(NotApplied(rejection)))} {
+		_, ok := CodeOf(err)
+		require.False(t, ok, "an accepted Update is not rejected")
+		require.True(t, RetryLater(err))
+	}
+	require.ErrorIs(t, NotApplied(rejection), rejection)
+	require.True(t, RetryLater(rejection))
+	require.False(t, RetryLater(NotApplied(Reject(CodeClosing, "closing"))))
+	require.False(t, RetryLater(nil))
+}
+
+func Test_RateLimit(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(func(ctx workflow.Context) error {
+		limiter := NewRateLimiter(2, time.Minute)
+		validator := RateLimit[int, arg](limiter)
+		steps := []struct {
+			wait time.Duration
+			code Code
+		}{
+			{0, ""},
+			{10 * time.Second, ""},
+			{10 * time.Second, CodeRateLimited},
+			// The first Update leaves the window.
+			{41 * time.Second, ""},
+			{0, CodeRateLimited},
+		}
+		for i, step := range steps {
+			err := workflow.Sleep(ctx, step.wait)
+			if err != nil {
+				return err
+			}
+			err = validator(ctx, 0, arg{})
+			if step.code == "" {
+				require.NoError(t, err, i)
+				limiter.Record(ctx)
+				continue
+			}
+			code, _ := CodeOf(err)
+			require.Equal(t, step.code, code, i)
+		}
+		return nil
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+}
@@ -0,0 +1,69 @@
+package yourupdate
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/client"
+)
+
+/*
+A Client that sends an Update to a Workflow that is not running gets a NotFound error.
+WithStart handles first use, and Workflows that finished after their idle timeout:
+when the Update finds no running Workflow, WithStart starts it and sends the Update again.
+
+Many processes can call WithStart at the same time.
+Only one of them starts the Workflow; the others get the run that was started, because
+WorkflowExecutionErrorWhenAlreadyStarted is false.
+Use the same Update Id on every attempt, so that an Update is applied at most once per run.
+*/
+
+// withStartAttempts is how many times WithStart sends the Update.
+// The Workflow can finish between the start and the Update, so more than two attempts may be needed.
+const withStartAttempts = 3
+
+// WithStart calls update, and if the Workflow doesn't exist, starts workflow with param and calls update again.
+// update must target the current run, that is, leave the Run Id empty.
+func WithStart(ctx context.Context, c client.Client, options client.StartWorkflowOptions, workflow interface{}, param WFParam, update func(ctx context.Context) error) error {
+	options.WorkflowExecutionErrorWhenAlreadyStarted = false
+	for attempt := 1; ; attempt++ {
+		err := update(ctx)
+		var notFound *serviceerror.NotFound
+		if !errors.As(err, &notFound) || attempt == withStartAttempts {
+			return err
+		}
+		_, err = c.ExecuteWorkflow(ctx, options, workflow, param)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// AddWithStart sends arg as a YourUpdateName Update to the YourUpdateWFID execution,
+// starting YourUpdatableWorkflow with a zero count if it isn't running.
+// It sets arg.UpdateID to a new Update Id if it is empty.
+func AddWithStart(ctx context.Context, c client.Client, arg YourUpdateArg) (YourUpdateResult, error) {
+	if arg.UpdateID == "" {
+		arg.UpdateID = uuid.NewString()
+	}
+	options := client.StartWorkflowOptions{
+		ID:        YourUpdateWFID,
+		TaskQueue: TaskQueueName,
+	}
+	var result YourUpdateResult
+	err := WithStart(ctx, c, options, YourUpdatableWorkflow, WFParam{}, func(ctx context.Context) error {
+		handle, err := c.UpdateWorkflowWithOptions(ctx, &client.UpdateWorkflowWithOptionsRequest{
+			UpdateID:   arg.UpdateID,
+			WorkflowID: YourUpdateWFID,
+			UpdateName: YourUpdateName,
+			Args:       []interface{}{arg},
+		})
+		if err != nil {
+			return err
+		}
+		return handle.Get(ctx, &result)
+	})
+	return result, err
+}
@@ -0,0 +1,111 @@
+package yourupdate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/mocks"
+)
+
+// updateHandle is a completed client.WorkflowUpdateHandle.
+type updateHandle struct {
+	client.WorkflowUpdateHandle
+	result YourUpdateResult
+}
+
+func (h *updateHandle) Get(ctx context.Context, valuePtr interface{}) error {
+	*valuePtr.(*YourUpdateResult) = h.result
+	return nil
+}
+
+func isAddRequest(updateID string) interface{} {
+	return mock.MatchedBy(func(req *client.UpdateWorkflowWithOptionsRequest) bool {
+		return req.WorkflowID == YourUpdateWFID && req.RunID == "" && req.UpdateName == YourUpdateName && req.UpdateID == updateID
+	})
+}
+
+func isStartOptions(options client.StartWorkflowOptions) bool {
+	return options.ID == YourUpdateWFID && options.TaskQueue == TaskQueueName && !options.WorkflowExecutionErrorWhenAlreadyStarted
+}
+
+func Test_AddWithStart_Running(t *testing.T) {
+	c := &mocks.Client{}
+	c.On("UpdateWorkflowWithOptions", mock.Anything, isAddRequest("update-1")).
+		Return(&updateHandle{result: YourUpdateResult{Total: 7}}, nil).Once()
+
+	result, err := AddWithStart(context.Background(), c, YourUpdateArg{Add: 2, UpdateID: "update-1"})
+	require.NoError(t, err)
+	require.Equal(t, YourUpdateResult{Total: 7}, result)
+	c.AssertExpectations(t)
+	c.AssertNotCalled(t, "ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_AddWithStart_StartsAndRetries(t *testing.T) {
+	c := &mocks.Client{}
+	c.On("UpdateWorkflowWithOptions", mock.Anything, isAddRequest("update-1")).
+		Return(nil, serviceerror.NewNotFound("workflow not found")).Once()
+	c.On("ExecuteWorkflow", mock.Anything, mock.MatchedBy(isStartOptions), mock.Anything, WFParam{}).
+		Return(&mocks.WorkflowRun{}, nil).Once()
+	c.On("UpdateWorkflowWithOptions", mock.Anything, isAddRequest("update-1")).
+		Return(&updateHandle{result: YourUpdateResult{Total: 2}}, nil).Once()
+
+	result, err := AddWithStart(context.Background(), c, YourUpdateArg{Add: 2, UpdateID: "update-1"})
+	require.NoError(t, err)
+	require.Equal(t, YourUpdateResult{Total: 2}, result)
+	c.AssertExpectations(t)
+}
+
+func Test_AddWithStart_GeneratesOneUpdateID(t *testing.T) {
+	c := &mocks.Client{}
+	var updateIDs []string
+	c.On("UpdateWorkflowWithOptions", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			updateIDs = append(updateIDs, args.Get(1).(*client.UpdateWorkflowWithOptionsRequest).UpdateID)
+		}).
+		Return(nil, serviceerror.NewNotFound("workflow not found")).Once()
+	c.On("ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&mocks.WorkflowRun{}, nil).Once()
+	c.On("UpdateWorkflowWithOptions", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			updateIDs = append(updateIDs, args.Get(1).(*client.UpdateWorkflowWithOptionsRequest).UpdateID)
+		}).
+		Return(&updateHandle{}, nil).Once()
+
+	_, err := AddWithStart(context.Background(), c, YourUpdateArg{Add: 2})
+	require.NoError(t, err)
+	// Both attempts use the same generated Update Id.
+	require.Len(t, updateIDs, 2)
+	require.NotEmpty(t, updateIDs[0])
+	require.Equal(t, updateIDs[0], updateIDs[1])
+}
+
+func Test_WithStart_GivesUp(t *testing.T) {
+	c := &mocks.Client{}
+	c.On("ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&mocks.WorkflowRun{}, nil)
+	calls := 0
+	notFound := serviceerror.NewNotFound("workflow not found")
+	err := WithStart(context.Background(), c, client.StartWorkflowOptions{ID: "id"}, "YourUpdatableWorkflow", WFParam{}, func(ctx context.Context) error {
+		calls++
+		return notFound
+	})
+	require.ErrorIs(t, err, notFound)
+	require.Equal(t, withStartAttempts, calls)
+	c.AssertNumberOfCalls(t, "ExecuteWorkflow", withStartAttempts-1)
+}
+
+func Test_WithStart_ReturnsStartErrors(t *testing.T) {
+	c := &mocks.Client{}
+	startErr := errors.New("permission denied")
+	c.On("ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, startErr)
+	err := WithStart(context.Background(), c, client.StartWorkflowOptions{ID: "id"}, "YourUpdatableWorkflow", WFParam{}, func(ctx context.Context) error {
+		return serviceerror.NewNotFound("workflow not found")
+	})
+	require.ErrorIs(t, err, startErr)
+}
@@ -2,24 +2,48 @@ package main
 
 import (
 	"log"
+	"os"
+	"path/filepath"
 
 	"go.temporal.io/sdk/client"
 	"go.temporal.io/sdk/worker"
 
+	"documentation-samples-go/codec"
 	"documentation-samples-go/yourupdate"
+	"documentation-samples-go/yourupdate/store"
 )
 
+// storeDirEnvVar names the directory where PersistentUpdatableWorkflow stores its totals.
+const storeDirEnvVar = "YOURUPDATE_STORE_DIR"
+
 func main() {
+	// Encrypt Payloads with the keys in the keyfile.
+	dataConverter, err := codec.NewDataConverter()
+	if err != nil {
+		log.Fatalln("Unable to create Data Converter", err)
+	}
 	temporalClient, err := client.Dial(client.Options{
-		HostPort: client.DefaultHostPort,
+		HostPort:      client.DefaultHostPort,
+		DataConverter: dataConverter,
 	})
 	if err != nil {
 		log.Fatalln("Unable to create client", err)
 	}
 	defer temporalClient.Close()
+	// Persist the totals in files, standing in for a real datastore.
+	storeDir := os.Getenv(storeDirEnvVar)
+	if storeDir == "" {
+		storeDir = filepath.Join(os.TempDir(), "yourupdate-totals")
+	}
+	fileStore, err := store.NewFileStore(storeDir)
+	if err != nil {
+		log.Fatalln("Unable to create store", err)
+	}
 	w := worker.New(temporalClient, yourupdate.TaskQueueName, worker.Options{})
 	w.RegisterWorkflow(yourupdate.YourUpdatableWorkflow)
 	w.RegisterWorkflow(yourupdate.UpdatableWorkflowWithValidator)
+	w.RegisterWorkflow(yourupdate.PersistentUpdatableWorkflow)
+	w.RegisterActivity(&yourupdate.PersistActivities{Store: fileStore})
 	err = w.Run(worker.InterruptCh())
 	if err != nil {
 		log.Fatalln("Unable to start worker", err)
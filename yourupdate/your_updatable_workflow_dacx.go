@@ -1,10 +1,11 @@
 package yourupdate
 
 import (
-	"fmt"
 	"time"
 
 	"go.temporal.io/sdk/workflow"
+
+	"documentation-samples-go/yourupdate/validate"
 )
 
 /*
@@ -33,6 +34,9 @@ const YourValidUpdateWFID = "validating_updatable_workflow"
 // YourUpdateArg defines the structure of the Update argument.
 type YourUpdateArg struct {
 	Add int
+	// UpdateID is recorded in the audit log.
+	// Set it to the Update Id sent with UpdateWorkflowWithOptions.
+	UpdateID string
 }
 
 // YourUpdateResult defines the structure of the Update result.
@@ -43,6 +47,15 @@ type YourUpdateResult struct {
 // WFParam defines the structure of thw Workflow argument.
 type WFParam struct {
 	StartCount int
+	// IdleTimeout is how long the Workflow waits for the next Update before finishing.
+	// It defaults to DefaultIdleTimeout.
+	IdleTimeout time.Duration
+	// MaxUpdatesPerRun is how many Updates a run accepts before it continues as new.
+	// It defaults to DefaultMaxUpdatesPerRun.
+	MaxUpdatesPerRun int
+	// MaxHistoryLength is how many history Events a run keeps before it continues as new.
+	// It defaults to DefaultMaxHistoryLength.
+	MaxHistoryLength int
 }
 
 // WFResult defines the structure of the Worfklow result.
@@ -63,13 +76,29 @@ Update handlers, unlike Query handlers, can change Workflow state.
 */
 
 // YourUpdatableWorkflow is a Workflow Definition.
-// This Workflow sets an Update handler and then sleeps for a minute.
-// After setting the Update hanlder it sleeps for 1 minutue.
-// Updates can be sent to the Workflow during this time.
+// This Workflow sets an Update handler and then waits for Updates.
+// It finishes when it receives the close Signal or when no Update arrives for the idle timeout,
+// and continues as new after many Updates.
 func YourUpdatableWorkflow(ctx workflow.Context, param WFParam) (WFResult, error) {
 	counter := param.StartCount
-	err := workflow.SetUpdateHandler(ctx, YourUpdateName, func(ctx workflow.Context, arg YourUpdateArg) (YourUpdateResult, error) {
+	lc := newLifecycle(ctx, param)
+	audit := newAuditLog(MaxAuditLogEntries)
+	err := setQueryHandlers(ctx, &counter, audit)
+	if err != nil {
+		return WFResult{}, err
+	}
+	err = setOperationHandler(ctx, &counter, lc, audit)
+	if err != nil {
+		return WFResult{}, err
+	}
+	err = workflow.SetUpdateHandler(ctx, YourUpdateName, func(ctx workflow.Context, arg YourUpdateArg) (YourUpdateResult, error) {
+		err := lc.begin()
+		if err != nil {
+			return YourUpdateResult{}, err
+		}
+		defer lc.end()
 		counter += arg.Add
+		audit.add(ctx, AuditEntry{UpdateID: arg.UpdateID, Operation: OperationAdd, Addend: arg.Add, Total: counter})
 		result := YourUpdateResult{
 			Total: counter,
 		}
@@ -78,8 +107,16 @@ func YourUpdatableWorkflow(ctx workflow.Context, param WFParam) (WFResult, error
 	if err != nil {
 		return WFResult{}, err
 	}
-	// Sleep for 60 seconds to have time to send Updates.
-	workflow.Sleep(ctx, 60*time.Second)
+	// Wait for Updates until the Workflow is closed or idle.
+	err = lc.wait(ctx, param.IdleTimeout)
+	if err != nil {
+		return WFResult{}, err
+	}
+	// Hand over to a new run once this run has handled enough Updates.
+	err = lc.continueAsNew(ctx, YourUpdatableWorkflow, param, counter)
+	if err != nil {
+		return WFResult{}, err
+	}
 	endTotal := WFResult{
 		EndTotal: counter,
 	}
@@ -102,27 +139,58 @@ The platform treats a panic in the Validator function as a rejection of the Upda
 
 // UpdatableWorkflowWithValidator is a Workflow Definition.
 // This Workflow Definition has an Update handler that uses the isPositive() validator function.
-// After setting the Update hanlder it sleeps for 1 minutue.
-// Updates can be sent to the Workflow during this time.
+// It finishes when it receives the close Signal or when no Update arrives for the idle timeout,
+// and continues as new after many Updates.
 func UpdatableWorkflowWithValidator(ctx workflow.Context, param WFParam) (WFResult, error) {
 	counter := param.StartCount
-	err := workflow.SetUpdateHandlerWithOptions(
+	lc := newLifecycle(ctx, param)
+	audit := newAuditLog(MaxAuditLogEntries)
+	err := setQueryHandlers(ctx, &counter, audit)
+	if err != nil {
+		return WFResult{}, err
+	}
+	err = setOperationHandler(ctx, &counter, lc, audit)
+	if err != nil {
+		return WFResult{}, err
+	}
+	err = workflow.SetUpdateHandlerWithOptions(
 		ctx, YourValidatedUpdateName,
 		func(ctx workflow.Context, arg YourUpdateArg) (YourUpdateResult, error) {
+			err := lc.begin()
+			if err != nil {
+				return YourUpdateResult{}, err
+			}
+			defer lc.end()
 			counter += arg.Add
+			audit.add(ctx, AuditEntry{UpdateID: arg.UpdateID, Operation: OperationAdd, Addend: arg.Add, Total: counter})
 			result := YourUpdateResult{
 				Total: counter,
 			}
 			return result, nil
 		},
 		// Set the isPositive validator.
-		workflow.UpdateHandlerOptions{Validator: isPositive},
+		// Reject Updates before they are recorded once the Workflow is closing.
+		workflow.UpdateHandlerOptions{Validator: func(ctx workflow.Context, arg YourUpdateArg) error {
+			err := lc.validate()
+			if err != nil {
+				return err
+			}
+			return isPositive(ctx, arg)
+		}},
 	)
 	if err != nil {
 		return WFResult{}, err
 	}
-	// Sleep for 60 seconds to have time to send Updates.
-	workflow.Sleep(ctx, 60*time.Second)
+	// Wait for Updates until the Workflow is closed or idle.
+	err = lc.wait(ctx, param.IdleTimeout)
+	if err != nil {
+		return WFResult{}, err
+	}
+	// Hand over to a new run once this run has handled enough Updates.
+	err = lc.continueAsNew(ctx, UpdatableWorkflowWithValidator, param, counter)
+	if err != nil {
+		return WFResult{}, err
+	}
 	endTotal := WFResult{
 		EndTotal: counter,
 	}
@@ -130,14 +198,14 @@ func UpdatableWorkflowWithValidator(ctx workflow.Context, param WFParam) (WFResu
 }
 
 // isPositive is a validator function.
-// It returns an error if the int value is below 1.
+// It returns an error with the validate.CodeOutOfRange code if the int value is below 1.
 // This function can not change the state of the Workflow.
 // workflow.Context can be used to log
 func isPositive(ctx workflow.Context, u YourUpdateArg) error {
 	log := workflow.GetLogger(ctx)
 	if u.Add < 1 {
 		log.Debug("Rejecting non-positive number, positive integers only", "UpdateValue", u.Add)
-		return fmt.Errorf("addend must be a positive integer (%v)", u.Add)
+		return validate.Reject(validate.CodeOutOfRange, "addend must be a positive integer (%v)", u.Add)
 	}
 	log.Debug("Accepting Update", "UpdateValue", u.Add)
 	return nil
@@ -153,7 +221,7 @@ tags:
 - code sample
 - workflow
 - update
-lines: 10-19, 69,71,80,87
+lines: 11-20, 82, 94, 109, 124
 @dacx */
 
 /* @dacx
@@ -166,7 +234,7 @@ tags:
 - code sample
 - workflow
 - update
-lines: 53-64, 69-77, 87
+lines: 66-77, 82-106, 124
 @dacx */
 
 /* @dacx
@@ -179,5 +247,5 @@ tags:
 - code sample
 - workflow
 - update
-lines: 89-111, 117-123, 130-144
+lines: 126-158, 170-183, 198-212
 @dacx */
@@ -0,0 +1,173 @@
+package yourupdate
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+
+	"documentation-samples-go/yourupdate/validate"
+)
+
+// updateCallbacks records the outcome of an Update sent in the test environment.
+type updateCallbacks struct {
+	accepted bool
+	rejected error
+	result   interface{}
+	err      error
+}
+
+func (u *updateCallbacks) Accept()          { u.accepted = true }
+func (u *updateCallbacks) Reject(err error) { u.rejected = err }
+func (u *updateCallbacks) Complete(success interface{}, err error) {
+	u.result = success
+	u.err = err
+}
+
+// historyFile is the Event History of an UpdatableWorkflowWithValidator execution, recorded from a Temporal Service,
+// that started at 0, was sent the Updates Add 5, Add -1 and Add 3, and was closed by the close Signal.
+// Its Payloads are decoded. The README explains how it was recorded.
+const historyFile = "your_updatable_workflow_history.json"
+
+func Test_YourUpdatableWorkflow_AppliesUpdates(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	updates := []*updateCallbacks{{}, {}}
+	env.RegisterDelayedCallback(func() {
+		env.UpdateWorkflow(YourUpdateName, updates[0], YourUpdateArg{Add: 5})
+	}, time.Second)
+	env.RegisterDelayedCallback(func() {
+		// Without a validator, non-positive numbers are applied too.
+		env.UpdateWorkflow(YourUpdateName, updates[1], YourUpdateArg{Add: -2})
+	}, 2*time.Second)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(YourCloseSignalName, nil)
+	}, 3*time.Second)
+
+	env.ExecuteWorkflow(YourUpdatableWorkflow, WFParam{StartCount: 1})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	for i, total := range []int{6, 4} {
+		require.True(t, updates[i].accepted)
+		require.NoError(t, updates[i].err)
+		require.Equal(t, YourUpdateResult{Total: total}, updates[i].result)
+	}
+	var result WFResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, 4, result.EndTotal)
+}
+
+func Test_UpdatableWorkflowWithValidator_isPositive(t *testing.T) {
+	tests := []struct {
+		name     string
+		add      int
+		accepted bool
+		endTotal int
+	}{
+		{"positive", 5, true, 15},
+		{"one", 1, true, 11},
+		{"zero", 0, false, 10},
+		{"negative", -3, false, 10},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			testSuite := &testsuite.WorkflowTestSuite{}
+			env := testSuite.NewTestWorkflowEnvironment()
+			update := &updateCallbacks{}
+			env.RegisterDelayedCallback(func() {
+				env.UpdateWorkflow(YourValidatedUpdateName, update, YourUpdateArg{Add: test.add})
+			}, time.Second)
+			env.RegisterDelayedCallback(func() {
+				env.SignalWorkflow(YourCloseSignalName, nil)
+			}, 2*time.Second)
+
+			env.ExecuteWorkflow(UpdatableWorkflowWithValidator, WFParam{StartCount: 10})
+			require.True(t, env.IsWorkflowCompleted())
+			require.NoError(t, env.GetWorkflowError())
+			require.Equal(t, test.accepted, update.accepted)
+			if test.accepted {
+				require.NoError(t, update.rejected)
+				require.Equal(t, YourUpdateResult{Total: test.endTotal}, update.result)
+			} else {
+				code, ok := validate.CodeOf(update.rejected)
+				require.True(t, ok)
+				require.Equal(t, validate.CodeOutOfRange, code)
+				// The handler never ran.
+				require.Nil(t, update.result)
+			}
+			var result WFResult
+			require.NoError(t, env.GetWorkflowResult(&result))
+			require.Equal(t, test.endTotal, result.EndTotal)
+		})
+	}
+}
+
+func Test_UpdatableWorkflowWithValidator_RejectedUpdatesAreNotInAuditLog(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	for i, add := range []int{5, -1, 3} {
+		add := add
+		env.RegisterDelayedCallback(func() {
+			env.UpdateWorkflow(YourValidatedUpdateName, &updateCallbacks{}, YourUpdateArg{Add: add})
+		}, time.Duration(i+1)*time.Second)
+	}
+	env.RegisterDelayedCallback(func() {
+		value, err := env.QueryWorkflow(YourAuditLogQueryName, AuditLogPageRequest{})
+		require.NoError(t, err)
+		var page AuditLogPage
+		require.NoError(t, value.Get(&page))
+		require.Len(t, page.Entries, 2)
+		require.Equal(t, []int{5, 3}, []int{page.Entries[0].Addend, page.Entries[1].Addend})
+		env.SignalWorkflow(YourCloseSignalName, nil)
+	}, 4*time.Second)
+
+	env.ExecuteWorkflow(UpdatableWorkflowWithValidator, WFParam{})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var result WFResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, 8, result.EndTotal)
+}
+
+func Test_UpdatableWorkflowWithValidator_Replay(t *testing.T) {
+	replayer := worker.NewWorkflowReplayer()
+	replayer.RegisterWorkflow(UpdatableWorkflowWithValidator)
+	err := replayer.ReplayWorkflowHistoryFromJSONFile(nil, historyFile)
+	require.NoError(t, err)
+}
+
+func Test_UpdatableWorkflowWithValidator_HistoryHasOnlyAcceptedUpdates(t *testing.T) {
+	f, err := os.Open(historyFile)
+	require.NoError(t, err)
+	defer f.Close()
+	history, err := client.HistoryFromJSON(f, client.HistoryJSONOptions{})
+	require.NoError(t, err)
+
+	dataConverter := converter.GetDefaultDataConverter()
+	var adds []int
+	for _, event := range history.Events {
+		switch event.GetEventType() {
+		case enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_UPDATE_ACCEPTED:
+			var arg YourUpdateArg
+			input := event.GetWorkflowExecutionUpdateAcceptedEventAttributes().GetAcceptedRequest().GetInput()
+			require.Equal(t, YourValidatedUpdateName, input.GetName())
+			require.NoError(t, dataConverter.FromPayloads(input.GetArgs(), &arg))
+			adds = append(adds, arg.Add)
+		case enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_UPDATE_REJECTED:
+			t.Fatalf("rejected Update recorded in event %d", event.GetEventId())
+		case enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_COMPLETED:
+			var result WFResult
+			payloads := event.GetWorkflowExecutionCompletedEventAttributes().GetResult()
+			require.NoError(t, dataConverter.FromPayloads(payloads, &result))
+			require.Equal(t, 8, result.EndTotal)
+		}
+	}
+	// The Add -1 Update was rejected by isPositive and left no Events.
+	require.Equal(t, []int{5, 3}, adds)
+}